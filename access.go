@@ -0,0 +1,105 @@
+package mydnshost_go_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseAccessLevel converts the given string into an AccessLevel, returning an error if it isn't a known level.
+// Matching is case-insensitive.
+func ParseAccessLevel(s string) (AccessLevel, error) {
+	level := AccessLevel(strings.ToLower(strings.TrimSpace(s)))
+	if !level.Known() {
+		return LevelNone, fmt.Errorf("unknown access level: %q", s)
+	}
+	return level, nil
+}
+
+// Known reports whether the level is one of those defined by this package. Levels decoded from the API that
+// aren't known (for example, roles added to MyDNSHost after this package was written) keep their raw value but
+// are treated as equivalent to LevelNone when compared.
+func (a AccessLevel) Known() bool {
+	switch a {
+	case LevelOwner, LevelAdmin, LevelWrite, LevelRead, LevelNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalJSON decodes an access level, normalising the case of known levels and preserving unknown ones as-is.
+func (a *AccessLevel) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == nil {
+		*a = LevelNone
+	} else if level, err := ParseAccessLevel(*s); err == nil {
+		*a = level
+	} else {
+		*a = AccessLevel(*s)
+	}
+	return nil
+}
+
+// rank gives the position of the level in the access hierarchy. Levels that aren't known rank alongside LevelNone.
+func (a AccessLevel) rank() int {
+	switch a {
+	case LevelOwner:
+		return 4
+	case LevelAdmin:
+		return 3
+	case LevelWrite:
+		return 2
+	case LevelRead:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Compare returns -1 if a grants less access than b, 1 if it grants more, and 0 if they are equivalent.
+// Levels are ordered none < read < write < admin < owner.
+func (a AccessLevel) Compare(b AccessLevel) int {
+	ra, rb := a.rank(), b.rank()
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether a grants at least as much access as b.
+func (a AccessLevel) AtLeast(b AccessLevel) bool {
+	return a.Compare(b) >= 0
+}
+
+// DomainAccess maps domain names to the level of access the current user has to them.
+type DomainAccess map[string]AccessLevel
+
+// AtLeast returns the subset of domains that the user has at least the given access level to.
+func (d DomainAccess) AtLeast(level AccessLevel) DomainAccess {
+	res := make(DomainAccess)
+	for domain := range d {
+		if d[domain].AtLeast(level) {
+			res[domain] = d[domain]
+		}
+	}
+	return res
+}
+
+// Readable returns the subset of domains that the user can read records from.
+func (d DomainAccess) Readable() DomainAccess {
+	return d.AtLeast(LevelRead)
+}
+
+// Writable returns the subset of domains that the user can modify records in.
+func (d DomainAccess) Writable() DomainAccess {
+	return d.AtLeast(LevelWrite)
+}
@@ -0,0 +1,160 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAccessLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    AccessLevel
+		wantErr bool
+	}{
+		{"owner", LevelOwner, false},
+		{"Admin", LevelAdmin, false},
+		{" write ", LevelWrite, false},
+		{"read", LevelRead, false},
+		{"none", LevelNone, false},
+		{"superuser", LevelNone, true},
+		{"", LevelNone, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAccessLevel(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAccessLevel(%q) error = %v, wantErr %t", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseAccessLevel(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAccessLevel_UnmarshalJSON(t *testing.T) {
+	var domains DomainAccess
+	input := `{"a.com": "owner", "b.com": "WRITE", "c.com": "auditor", "d.com": null}`
+	if err := json.Unmarshal([]byte(input), &domains); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := DomainAccess{
+		"a.com": LevelOwner,
+		"b.com": LevelWrite,
+		"c.com": AccessLevel("auditor"),
+		"d.com": LevelNone,
+	}
+	for domain, level := range want {
+		if domains[domain] != level {
+			t.Errorf("domains[%q] = %q, want %q", domain, domains[domain], level)
+		}
+	}
+
+	if domains["c.com"].Known() {
+		t.Errorf("unknown level %q reported as known", domains["c.com"])
+	}
+	if domains["c.com"].AtLeast(LevelRead) {
+		t.Errorf("unknown level %q should not grant read access", domains["c.com"])
+	}
+}
+
+func TestAccessLevel_MarshalJSON(t *testing.T) {
+	input := DomainAccess{"a.com": LevelAdmin, "b.com": AccessLevel("auditor")}
+	b, err := json.Marshal(input)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var output DomainAccess
+	if err := json.Unmarshal(b, &output); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	for domain := range input {
+		if output[domain] != input[domain] {
+			t.Errorf("round trip of %q = %q, want %q", domain, output[domain], input[domain])
+		}
+	}
+}
+
+func TestAccessLevel_Compare(t *testing.T) {
+	order := []AccessLevel{LevelNone, LevelRead, LevelWrite, LevelAdmin, LevelOwner}
+	for i := range order {
+		for j := range order {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := order[i].Compare(order[j]); got != want {
+				t.Errorf("%q.Compare(%q) = %d, want %d", order[i], order[j], got, want)
+			}
+		}
+	}
+}
+
+func TestClient_RevokeDomainAccess(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"access":{"owner@example.com":"Owner"}}}`, &req)
+
+	res, err := c.RevokeDomainAccess(context.Background(), "example.com", "colleague@example.com")
+	if err != nil {
+		t.Fatalf("RevokeDomainAccess() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com/access" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com/access", req.Method, req.URL.Path)
+	}
+	if len(res.Access) != 1 || res.Access["owner@example.com"] != LevelOwner {
+		t.Errorf("RevokeDomainAccess() access = %v, want owner@example.com: owner", res.Access)
+	}
+}
+
+func TestClient_GrantDomainsAccess(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, strings.TrimSpace(string(body))))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/1.0/domains/example.org/access" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"You do not have permission to change access"}`))
+			return
+		}
+		// new@example.com has no account, so isn't granted access.
+		_, _ = w.Write([]byte(`{"response":{"access":{"owner@example.com":"Owner","Ops@example.com":"Write"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	grants, err := c.GrantDomainsAccess(context.Background(), []string{"ops@example.com", "new@example.com"}, []string{"example.com", "example.org"}, LevelWrite)
+	if err == nil {
+		t.Errorf("GrantDomainsAccess() with a failing domain should return an error")
+	}
+
+	want := []string{
+		`POST /1.0/domains/example.com/access {"data":{"access":{"new@example.com":"write","ops@example.com":"write"}}}`,
+		`POST /1.0/domains/example.org/access {"data":{"access":{"new@example.com":"write","ops@example.com":"write"}}}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+
+	if len(grants) != 2 {
+		t.Fatalf("GrantDomainsAccess() = %+v, want a grant for each domain", grants)
+	}
+	if g := grants[0]; g.Domain != "example.com" || g.Err != nil || !reflect.DeepEqual(g.Granted, []string{"ops@example.com"}) || !reflect.DeepEqual(g.Missing, []string{"new@example.com"}) {
+		t.Errorf("grants[0] = %+v, want ops@example.com granted and new@example.com missing", g)
+	}
+	if g := grants[1]; g.Domain != "example.org" || g.Err == nil || len(g.Granted) != 0 || len(g.Missing) != 2 {
+		t.Errorf("grants[1] = %+v, want an error with both users missing", g)
+	}
+}
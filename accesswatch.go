@@ -0,0 +1,229 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultAccessWatchInterval = 5 * time.Minute
+
+// ErrAlreadyStarted is returned when starting a background component that is already running.
+var ErrAlreadyStarted = errors.New("already started")
+
+// AccessChangedEvent is emitted by an AccessWatcher when a level of access to a domain changes. Old is LevelNone
+// when access has been granted, and New is LevelNone when it has been revoked.
+type AccessChangedEvent struct {
+	Time   time.Time
+	Domain string
+	// User is the e-mail address of the user whose access changed, or blank if it is the current user's.
+	User string
+	Old  AccessLevel
+	New  AccessLevel
+}
+
+func (*AccessChangedEvent) event() {}
+
+// AccessWatcher periodically snapshots the current user's access to domains, and the access lists of domains the
+// user administers, and emits an AccessChangedEvent from the client for each difference it finds.
+type AccessWatcher struct {
+	Client *Client
+	// Interval between checks. Defaults to 5 minutes.
+	Interval time.Duration
+	// Users enables checking the per-user access lists of domains the current user is an admin or owner of.
+	Users bool
+
+	mu       sync.Mutex
+	domains  DomainAccess
+	users    map[string]map[string]AccessLevel
+	snapshot bool
+
+	lifecycle sync.Mutex
+	stop      context.CancelFunc
+	done      chan struct{}
+	err       error
+}
+
+// Check takes a snapshot of domain access and returns the changes since the previous snapshot, emitting an event
+// for each. The first call establishes the baseline and reports no changes.
+func (w *AccessWatcher) Check(ctx context.Context) ([]AccessChangedEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	domains, err := w.Client.Domains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]map[string]AccessLevel)
+	if w.Users {
+		for domain, level := range domains {
+			if !level.AtLeast(LevelAdmin) {
+				continue
+			}
+
+			res, err := w.Client.DomainUsers(ctx, domain)
+			if err != nil {
+				return nil, err
+			}
+			users[domain] = res.Access
+		}
+	}
+
+	var changes []AccessChangedEvent
+	if w.snapshot {
+		now := w.Client.now()
+		for _, domain := range changedKeys(w.domains, domains) {
+			changes = append(changes, AccessChangedEvent{
+				Time:   now,
+				Domain: domain,
+				Old:    levelOf(w.domains, domain),
+				New:    levelOf(domains, domain),
+			})
+		}
+
+		// User lists are only compared for domains in both snapshots, so that gaining or losing the ability to
+		// see a domain's access list isn't reported as every user's access changing.
+		for _, domain := range sortedKeys(users) {
+			previous, ok := w.users[domain]
+			if !ok {
+				continue
+			}
+
+			for _, user := range changedKeys(previous, users[domain]) {
+				changes = append(changes, AccessChangedEvent{
+					Time:   now,
+					Domain: domain,
+					User:   user,
+					Old:    levelOf(previous, user),
+					New:    levelOf(users[domain], user),
+				})
+			}
+		}
+	}
+
+	w.domains = domains
+	w.users = users
+	w.snapshot = true
+
+	for i := range changes {
+		event := changes[i]
+		w.Client.emit(&event)
+	}
+	return changes, nil
+}
+
+// Run checks domain access every Interval until ctx is cancelled or a check fails, returning the error that
+// stopped it.
+func (w *AccessWatcher) Run(ctx context.Context) error {
+	return w.run(ctx, ctx)
+}
+
+// Start runs the watcher in the background until Stop is called, ctx is cancelled or a check fails. Stop must be
+// called before the watcher can be started again.
+func (w *AccessWatcher) Start(ctx context.Context) error {
+	w.lifecycle.Lock()
+	defer w.lifecycle.Unlock()
+
+	if w.done != nil {
+		return ErrAlreadyStarted
+	}
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	w.stop = cancel
+	w.done = make(chan struct{})
+	w.err = nil
+
+	go func(done chan struct{}) {
+		err := w.run(ctx, waitCtx)
+		w.lifecycle.Lock()
+		w.err = err
+		w.lifecycle.Unlock()
+		close(done)
+	}(w.done)
+	return nil
+}
+
+// Stop stops a watcher started by Start. A check that is in progress is allowed to finish, so that its events
+// aren't lost, unless ctx is cancelled first. Stop returns the error that stopped the watcher, if it wasn't
+// stopped by Stop itself.
+func (w *AccessWatcher) Stop(ctx context.Context) error {
+	w.lifecycle.Lock()
+	stop, done := w.stop, w.done
+	w.lifecycle.Unlock()
+
+	if done == nil {
+		return nil
+	}
+
+	stop()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	w.lifecycle.Lock()
+	defer w.lifecycle.Unlock()
+	err := w.err
+	w.stop, w.done, w.err = nil, nil, nil
+	if err == context.Canceled {
+		err = nil
+	}
+	return err
+}
+
+// run checks domain access using checkCtx, and waits between checks using waitCtx. Cancelling only waitCtx stops
+// the watcher without interrupting a check.
+func (w *AccessWatcher) run(checkCtx, waitCtx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultAccessWatchInterval
+	}
+
+	for {
+		if _, err := w.Check(checkCtx); err != nil {
+			return err
+		}
+
+		if !w.Client.sleep(waitCtx, interval) {
+			return waitCtx.Err()
+		}
+	}
+}
+
+// changedKeys returns the keys whose access levels differ between two maps, in order.
+func changedKeys(old, new map[string]AccessLevel) []string {
+	var keys []string
+	for key := range old {
+		if levelOf(old, key) != levelOf(new, key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok && levelOf(new, key) != LevelNone {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(m map[string]map[string]AccessLevel) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// levelOf returns the level for key in m, treating missing entries as LevelNone.
+func levelOf(m map[string]AccessLevel, key string) AccessLevel {
+	if level, ok := m[key]; ok && level != "" {
+		return level
+	}
+	return LevelNone
+}
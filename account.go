@@ -0,0 +1,78 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RegisterResponse describes a newly registered account.
+type RegisterResponse struct {
+	Id       int    `json:"id"`
+	Email    string `json:"email"`
+	RealName string `json:"realname"`
+}
+
+// Register creates a new account. It does not require authentication, and the client's authenticator is not used.
+// The API sends a confirmation code to the e-mail address, which must be passed to ConfirmRegistration along with
+// the returned account ID before the account can be used.
+func (c *Client) Register(ctx context.Context, email, realName, password string) (*RegisterResponse, error) {
+	res, err := c.requestWithAuth(ctx, nil, http.MethodPost, "register", apiRequest{
+		Data: struct {
+			Email    string `json:"email"`
+			RealName string `json:"realname"`
+			Password string `json:"password"`
+		}{
+			Email:    email,
+			RealName: realName,
+			Password: password,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RegisterResponse{}
+	return response, c.codec().Unmarshal(*res.Response, response)
+}
+
+// ConfirmRegistration activates a newly registered account using the code sent to its e-mail address. It does not
+// require authentication.
+func (c *Client) ConfirmRegistration(ctx context.Context, id int, code string) error {
+	_, err := c.requestWithAuth(ctx, nil, http.MethodPost, fmt.Sprintf("register/confirm/%d", id), apiRequest{
+		Data: struct {
+			Code string `json:"code"`
+		}{
+			Code: code,
+		},
+	})
+	return err
+}
+
+// RequestPasswordReset asks the API to send a password reset e-mail to the account with the given address. It does
+// not require authentication. The e-mail contains the account ID and a code to pass to ResetPassword.
+func (c *Client) RequestPasswordReset(ctx context.Context, email string) error {
+	_, err := c.requestWithAuth(ctx, nil, http.MethodPost, "forgotpassword", apiRequest{
+		Data: struct {
+			Email string `json:"email"`
+		}{
+			Email: email,
+		},
+	})
+	return err
+}
+
+// ResetPassword sets a new password for an account using the code from a password reset e-mail. It does not require
+// authentication.
+func (c *Client) ResetPassword(ctx context.Context, id int, code, password string) error {
+	_, err := c.requestWithAuth(ctx, nil, http.MethodPost, fmt.Sprintf("forgotpassword/confirm/%d", id), apiRequest{
+		Data: struct {
+			Code     string `json:"code"`
+			Password string `json:"password"`
+		}{
+			Code:     code,
+			Password: password,
+		},
+	})
+	return err
+}
@@ -0,0 +1,193 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	defaultACMETTL          = 60
+	defaultACMEPollInterval = 5 * time.Second
+)
+
+// ACMEChallenge is a DNS-01 challenge record to be published for an ACME certificate order.
+type ACMEChallenge struct {
+	// Domain is the MyDNSHost domain the record will be created in.
+	Domain string
+	// Name is the record name relative to Domain, e.g. "_acme-challenge" or "_acme-challenge.www".
+	Name string
+	// Value is the TXT content requested by the ACME server.
+	Value string
+}
+
+// ACMEOptions controls how challenges are published and checked.
+type ACMEOptions struct {
+	// TTL of the created TXT records. Defaults to 60 seconds.
+	TTL int
+	// Delay is the pause between API calls for each domain, to avoid overwhelming the API with large batches.
+	Delay time.Duration
+	// PollInterval is how often to check whether records have propagated. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// LookupTXT resolves TXT records for a fully-qualified name. Defaults to net.DefaultResolver.LookupTXT.
+	LookupTXT func(ctx context.Context, name string) ([]string, error)
+}
+
+// ACMEDomainStatus tracks the progress of the challenges for a single domain.
+type ACMEDomainStatus struct {
+	Domain     string
+	Challenges []ACMEChallenge
+	// Records are the TXT records that were created for the challenges.
+	Records []ExistingRecord
+	// Propagated is set once every challenge value has been observed in DNS.
+	Propagated bool
+	// Err is the most recent error encountered for this domain, if any.
+	Err error
+}
+
+// PresentACMEChallenges creates the TXT records for all of the given challenges, using a single API call for each
+// domain. A status is returned for every domain; domains whose records could not be created have Err set.
+func (c *Client) PresentACMEChallenges(ctx context.Context, challenges []ACMEChallenge, opts ACMEOptions) []*ACMEDomainStatus {
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = defaultACMETTL
+	}
+
+	var statuses []*ACMEDomainStatus
+	byDomain := make(map[string]*ACMEDomainStatus)
+	for _, challenge := range challenges {
+		status, ok := byDomain[challenge.Domain]
+		if !ok {
+			status = &ACMEDomainStatus{Domain: challenge.Domain}
+			byDomain[challenge.Domain] = status
+			statuses = append(statuses, status)
+		}
+		status.Challenges = append(status.Challenges, challenge)
+	}
+
+	for i, status := range statuses {
+		if i > 0 && !c.sleep(ctx, opts.Delay) {
+			status.Err = ctx.Err()
+			continue
+		}
+
+		operations := make([]RecordOperation, len(status.Challenges))
+		for j, challenge := range status.Challenges {
+			operations[j] = CreateRecord(Record{
+				Name:    challenge.Name,
+				Type:    "TXT",
+				Content: challenge.Value,
+				TTL:     ttl,
+			})
+		}
+
+		res, err := c.ModifyRecords(ctx, status.Domain, operations...)
+		if err != nil {
+			status.Err = err
+			continue
+		}
+
+		for j := range res.Changed {
+			status.Records = append(status.Records, res.Changed[j].ExistingRecord)
+		}
+	}
+
+	return statuses
+}
+
+// WaitForACMEChallenges polls DNS until every challenge value for the given domains is visible, or ctx is done.
+// Domains that failed to present their challenges are skipped. It returns ctx's error if not all domains propagated.
+func (c *Client) WaitForACMEChallenges(ctx context.Context, statuses []*ACMEDomainStatus, opts ACMEOptions) error {
+	lookup := opts.LookupTXT
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupTXT
+	}
+
+	interval := opts.PollInterval
+	if interval == 0 {
+		interval = defaultACMEPollInterval
+	}
+
+	for {
+		pending := 0
+		for _, status := range statuses {
+			if status.Propagated || len(status.Records) == 0 {
+				continue
+			}
+
+			status.Propagated, status.Err = challengesVisible(ctx, lookup, status.Challenges)
+			if !status.Propagated {
+				pending++
+			}
+		}
+
+		if pending == 0 {
+			return nil
+		}
+
+		if !c.sleep(ctx, interval) {
+			return fmt.Errorf("%d domains did not propagate: %w", pending, ctx.Err())
+		}
+	}
+}
+
+// CleanupACMEChallenges deletes the records created by PresentACMEChallenges, pausing between domains as configured.
+// It returns the first error encountered, and records each domain's error in its status.
+func (c *Client) CleanupACMEChallenges(ctx context.Context, statuses []*ACMEDomainStatus, opts ACMEOptions) error {
+	var firstErr error
+	for i, status := range statuses {
+		if len(status.Records) == 0 {
+			continue
+		}
+
+		if i > 0 && !c.sleep(ctx, opts.Delay) {
+			return ctx.Err()
+		}
+
+		operations := make([]RecordOperation, len(status.Records))
+		for j := range status.Records {
+			operations[j] = DeleteRecord(status.Records[j].Id)
+		}
+
+		if _, err := c.ModifyRecords(ctx, status.Domain, operations...); err != nil {
+			status.Err = err
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		status.Records = nil
+	}
+	return firstErr
+}
+
+// challengesVisible checks whether all of the challenge values can be resolved.
+func challengesVisible(ctx context.Context, lookup func(context.Context, string) ([]string, error), challenges []ACMEChallenge) (bool, error) {
+	for _, challenge := range challenges {
+		name := strings.TrimSuffix(challenge.Domain, ".")
+		if challenge.Name != "" {
+			name = challenge.Name + "." + name
+		}
+
+		values, err := lookup(ctx, name)
+		if err != nil {
+			return false, err
+		}
+
+		found := false
+		for _, v := range values {
+			if v == challenge.Value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_ACMEChallenges(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s", r.URL.Path, strings.TrimSpace(string(body))))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/1.0/domains/example.org/"):
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"You do not have access to this domain"}`))
+		case strings.Contains(string(body), "delete"):
+			_, _ = w.Write([]byte(`{"response":{"serial":"3","changed":[]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"response":{"serial":"2","changed":[
+				{"id":"21","name":"_acme-challenge","type":"TXT","content":"token1","ttl":"60"},
+				{"id":"22","name":"_acme-challenge.www","type":"TXT","content":"token2","ttl":"60"}
+			]}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/", Clock: instantClock{}}
+
+	// Each name only becomes visible the second time it is looked up.
+	published := map[string]string{
+		"_acme-challenge.example.com":     "token1",
+		"_acme-challenge.www.example.com": "token2",
+	}
+	lookups := make(map[string]int)
+	opts := ACMEOptions{LookupTXT: func(ctx context.Context, name string) ([]string, error) {
+		lookups[name]++
+		if lookups[name] < 2 {
+			return nil, nil
+		}
+		return []string{published[name]}, nil
+	}}
+
+	statuses := c.PresentACMEChallenges(context.Background(), []ACMEChallenge{
+		{Domain: "example.com", Name: "_acme-challenge", Value: "token1"},
+		{Domain: "example.org", Name: "_acme-challenge", Value: "token3"},
+		{Domain: "example.com", Name: "_acme-challenge.www", Value: "token2"},
+	}, opts)
+
+	if len(statuses) != 2 || statuses[0].Domain != "example.com" || statuses[1].Domain != "example.org" {
+		t.Fatalf("PresentACMEChallenges() = %+v, want a status for each domain", statuses)
+	}
+	if statuses[0].Err != nil || len(statuses[0].Records) != 2 || statuses[0].Records[1].Id != 22 {
+		t.Errorf("example.com status = %+v, want records 21 and 22", statuses[0])
+	}
+	if statuses[1].Err == nil || len(statuses[1].Records) != 0 {
+		t.Errorf("example.org status = %+v, want an error", statuses[1])
+	}
+
+	if err := c.WaitForACMEChallenges(context.Background(), statuses, opts); err != nil {
+		t.Fatalf("WaitForACMEChallenges() error = %v", err)
+	}
+	if !statuses[0].Propagated || statuses[1].Propagated {
+		t.Errorf("propagated = %v, %v, want only example.com", statuses[0].Propagated, statuses[1].Propagated)
+	}
+
+	if err := c.CleanupACMEChallenges(context.Background(), statuses, opts); err != nil {
+		t.Fatalf("CleanupACMEChallenges() error = %v", err)
+	}
+
+	want := []string{
+		`/1.0/domains/example.com/records {"data":{"records":[` +
+			`{"name":"_acme-challenge","type":"TXT","content":"token1","ttl":60},` +
+			`{"name":"_acme-challenge.www","type":"TXT","content":"token2","ttl":60}]}}`,
+		`/1.0/domains/example.org/records {"data":{"records":[{"name":"_acme-challenge","type":"TXT","content":"token3","ttl":60}]}}`,
+		`/1.0/domains/example.com/records {"data":{"records":[{"id":21,"delete":true},{"id":22,"delete":true}]}}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+	if len(statuses[0].Records) != 0 {
+		t.Errorf("CleanupACMEChallenges() left records %v", statuses[0].Records)
+	}
+}
@@ -23,3 +23,27 @@ func (a *DomainKeyAuthenticator) AddHeaders(r *http.Request) {
 	r.Header["X-Domain"] = []string{a.Domain}
 	r.Header["X-Domain-Key"] = []string{a.Key}
 }
+
+// SessionAuthenticator authenticates using a session ID, as returned by Client.Login
+type SessionAuthenticator struct {
+	SessionID string `json:"session"`
+}
+
+func (a *SessionAuthenticator) AddHeaders(r *http.Request) {
+	r.Header["X-Session-ID"] = []string{a.SessionID}
+}
+
+// passwordAuthenticator authenticates using a username (e-mail address) and password. It is only used to create
+// sessions, as sending a password with every request is best avoided.
+type passwordAuthenticator struct {
+	user     string
+	password string
+	code     string
+}
+
+func (a *passwordAuthenticator) AddHeaders(r *http.Request) {
+	r.SetBasicAuth(a.user, a.password)
+	if a.code != "" {
+		r.Header["X-2FA-Key"] = []string{a.code}
+	}
+}
@@ -0,0 +1,191 @@
+package mydnshost_go_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const benchmarkZoneSize = 100000
+
+// syntheticRecords generates a zone's worth of distinct records.
+func syntheticRecords(n int) []ExistingRecord {
+	records := make([]ExistingRecord, n)
+	for i := range records {
+		records[i] = ExistingRecord{
+			Record: Record{
+				Name:    fmt.Sprintf("host%d", i),
+				Type:    "A",
+				Content: fmt.Sprintf("10.%d.%d.%d", (i>>16)&0xff, (i>>8)&0xff, i&0xff),
+				TTL:     3600,
+			},
+			Id:        i + 1,
+			ChangedAt: 1600000000,
+		}
+	}
+	return records
+}
+
+// apiRecordsResponse encodes records as the API does, with every scalar sent as a string.
+func apiRecordsResponse(records []ExistingRecord) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"records":[`)
+	for i := range records {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		r := records[i]
+		fmt.Fprintf(&buf, `{"id":"%d","name":"%s","type":"%s","content":"%s","ttl":"%d","priority":null,"disabled":"false","changed_at":"%d","changed_by":null}`,
+			r.Id, r.Name, r.Type, r.Content, r.TTL, r.ChangedAt)
+	}
+	buf.WriteString(`],"hasNS":"true","soa":{"primaryNS":"ns1.mydnshost.co.uk.","adminAddress":"dnsadmin.dataforce.org.uk.","serial":"2020091301","refresh":"86400","retry":"7200","expire":"2419200","min_ttl":"60"}}`)
+	return buf.Bytes()
+}
+
+// BenchmarkRecordsDecode decodes a Records response in the same way as Client.Records.
+func BenchmarkRecordsDecode(b *testing.B) {
+	data := apiRecordsResponse(syntheticRecords(benchmarkZoneSize))
+	c := &Client{}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wire := &wireRecordsResponse{}
+		if err := c.codec().Unmarshal(data, wire); err != nil {
+			b.Fatal(err)
+		}
+		if len(wire.response().Records) != benchmarkZoneSize {
+			b.Fatal("records were not decoded")
+		}
+	}
+}
+
+// BenchmarkClientRecords measures a complete request, including reading the response into a pooled buffer. The
+// zone is kept small enough for the buffer to be returned to the pool.
+func BenchmarkClientRecords(b *testing.B) {
+	data, err := json.Marshal(RecordsResponse{Records: syntheticRecords(1000)})
+	if err != nil {
+		b.Fatal(err)
+	}
+	body := append(append([]byte(`{"response":`), data...), '}')
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	b.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Records(context.Background(), "example.com"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkModifyRecordsEncode(b *testing.B) {
+	records := syntheticRecords(benchmarkZoneSize)
+	operations := make([]RecordOperation, len(records))
+	for i := range records {
+		operations[i] = ModifyRecord(records[i].Id, records[i].Record)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := (StandardCodec{}).Marshal(modifyRecordsRequest(operations)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseZone(b *testing.B) {
+	var zone bytes.Buffer
+	zone.WriteString("$ORIGIN example.com.\n$TTL 1h\n@ IN SOA ns1.mydnshost.co.uk. dnsadmin.dataforce.org.uk. ( 1 86400 7200 2419200 60 )\n")
+	for _, r := range syntheticRecords(benchmarkZoneSize) {
+		fmt.Fprintf(&zone, "%s %d IN %s %s ; host %d\n", r.Name, r.TTL, r.Type, r.Content, r.Id)
+	}
+	data := zone.String()
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		records, err := ParseZone(data, "example.com")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(records) != benchmarkZoneSize {
+			b.Fatal("records were not parsed")
+		}
+	}
+}
+
+// BenchmarkDiffRecords compares two copies of a zone, as CompareZones does, where a tenth of the records have
+// changed TTLs, a tenth are missing from the target and a tenth have been added to it.
+func BenchmarkDiffRecords(b *testing.B) {
+	source := syntheticRecords(benchmarkZoneSize)
+	target := make([]ExistingRecord, 0, len(source))
+	for i := range source {
+		r := source[i]
+		switch i % 10 {
+		case 0:
+			r.TTL = 300
+		case 1:
+			continue
+		case 2:
+			r.Name = "new-" + r.Name
+		}
+		target = append(target, r)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		diff := diffRecords("example.com", source, target)
+		if len(diff.Changed) != benchmarkZoneSize/10 || len(diff.Missing) != 2*benchmarkZoneSize/10 {
+			b.Fatalf("diff has %d changed and %d missing records", len(diff.Changed), len(diff.Missing))
+		}
+	}
+}
+
+func BenchmarkCreateRecord(b *testing.B) {
+	record := Record{Name: "www", Type: "A", Content: "192.0.2.1", TTL: 3600}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		CreateRecord(record)
+	}
+}
+
+func BenchmarkGroupRRSets(b *testing.B) {
+	records := syntheticRecords(benchmarkZoneSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GroupRRSets(records)
+	}
+}
+
+func TestModifyRecordsRequestAllocs(t *testing.T) {
+	operations := make([]RecordOperation, 1000)
+	for i := range operations {
+		operations[i] = DeleteRecord(i)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		modifyRecordsRequest(operations)
+	})
+	if allocs > 2 {
+		t.Errorf("modifyRecordsRequest allocated %.0f times for %d operations, want at most 2", allocs, len(operations))
+	}
+}
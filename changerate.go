@@ -0,0 +1,133 @@
+package mydnshost_go_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrChangeRateExceeded is returned by calls that would change a record more often than the client's
+// ChangeRatePolicy allows.
+var ErrChangeRateExceeded = errors.New("record changed too often")
+
+// ChangeRatePolicy limits how often the client changes any single record, to protect a zone from a runaway loop
+// or a flapping dynamic DNS updater. Records are identified by ID where a change specifies one, and otherwise by
+// name and type.
+//
+// When a change would exceed the limit, a ChangeRateExceededEvent is emitted and, unless AlertOnly is set, the
+// call returns an error wrapping ErrChangeRateExceeded without making any of its changes.
+type ChangeRatePolicy struct {
+	// Max is the number of times a record may be changed within Window.
+	Max int
+	// Window is the period over which changes are counted.
+	Window time.Duration
+	// AlertOnly allows changes that exceed the limit, only emitting an event.
+	AlertOnly bool
+}
+
+// ChangeRateExceededEvent is emitted when a change to a record exceeds the client's ChangeRatePolicy.
+type ChangeRateExceededEvent struct {
+	Time   time.Time
+	Domain string
+	// Record identifies the record, either as "#<id>" or as "<name> <type>".
+	Record string
+	// Count is the number of changes to the record within the policy's window, including this one.
+	Count int
+	// Allowed reports whether the change was made anyway, because the policy is AlertOnly.
+	Allowed bool
+}
+
+func (*ChangeRateExceededEvent) event() {}
+
+// checkChangeRate records changes to the given records of a domain, returning an error if any would exceed the
+// client's ChangeRatePolicy. If an error is returned, none of the changes are recorded.
+func (c *Client) checkChangeRate(domain string, records ...string) error {
+	policy := c.ChangeRate
+	if policy == nil || policy.Max <= 0 || policy.Window <= 0 || len(records) == 0 {
+		return nil
+	}
+
+	now := c.now()
+	cutoff := now.Add(-policy.Window)
+	domain = normaliseHost(domain)
+
+	var exceeded []*ChangeRateExceededEvent
+	c.mu.Lock()
+	if c.changes == nil {
+		c.changes = make(map[string][]time.Time)
+	}
+
+	counts := make(map[string]int, len(records))
+	flagged := make(map[string]bool)
+	for _, record := range records {
+		key := domain + "\x00" + record
+		if _, ok := counts[key]; !ok {
+			recent := c.changes[key][:0]
+			for _, t := range c.changes[key] {
+				if t.After(cutoff) {
+					recent = append(recent, t)
+				}
+			}
+			c.changes[key] = recent
+			counts[key] = len(recent)
+		}
+
+		counts[key]++
+		if counts[key] > policy.Max && !flagged[key] {
+			flagged[key] = true
+			exceeded = append(exceeded, &ChangeRateExceededEvent{
+				Time:    now,
+				Domain:  domain,
+				Record:  record,
+				Count:   counts[key],
+				Allowed: policy.AlertOnly,
+			})
+		}
+	}
+
+	if len(exceeded) == 0 || policy.AlertOnly {
+		for key, count := range counts {
+			for len(c.changes[key]) < count {
+				c.changes[key] = append(c.changes[key], now)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	for _, e := range exceeded {
+		c.emit(e)
+	}
+
+	if len(exceeded) > 0 && !policy.AlertOnly {
+		return fmt.Errorf("%w: %s in %s", ErrChangeRateExceeded, exceeded[0].Record, domain)
+	}
+	return nil
+}
+
+// changedRecords identifies the records changed by each operation, for checkChangeRate.
+func changedRecords(operations []RecordOperation) []string {
+	records := make([]string, 0, len(operations))
+	for i := range operations {
+		op := decodedOperation{}
+		if err := json.Unmarshal(operations[i], &op); err != nil {
+			continue
+		}
+
+		if op.Id != 0 {
+			records = append(records, recordByID(op.Id))
+		} else {
+			records = append(records, recordByName(op.Name, op.Type))
+		}
+	}
+	return records
+}
+
+func recordByID(id int) string {
+	return fmt.Sprintf("#%d", id)
+}
+
+func recordByName(name, recordType string) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", strings.ToLower(name), strings.ToUpper(recordType)))
+}
@@ -5,16 +5,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
 const apiHost = "api.mydnshost.co.uk"
 const apiVersion = "1.0"
 
+// DefaultBaseURL is the base URL of the public MyDNSHost API, used if a Client does not specify one.
+const DefaultBaseURL = "https://" + apiHost + "/" + apiVersion
+
 type apiResponse struct {
 	ResponseId string            `json:"respid"`
 	Method     string            `json:"method"`
@@ -36,6 +41,36 @@ type ClientAuthenticator interface {
 // to be provided that can supply credentials to the API.
 type Client struct {
 	Authenticator ClientAuthenticator
+	// BaseURL is the URL of the API, including the version, e.g. "http://localhost:8080/1.0". It can be used to connect
+	// to a self-hosted or staging instance of MyDNSHost. If blank, DefaultBaseURL is used.
+	BaseURL string
+	// HTTPClient is used to make requests to the API. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Codec is used to encode requests and decode responses. If nil, StandardCodec is used.
+	Codec Codec
+	// Retry configures automatic retries of failed requests. If nil, requests are not retried.
+	Retry *RetryPolicy
+	// RateLimitRetries is the number of times a request that is rate limited by the API will be retried after
+	// waiting for the period the API requests. Requests are not retried if the wait would exceed the context's
+	// deadline. If zero, a *RateLimitError is returned immediately.
+	RateLimitRetries int
+	// Freeze prevents changes being made to some or all domains. If nil, no domains are frozen.
+	Freeze *FreezePolicy
+	// ChangeRate limits how often any single record may be changed. If nil, changes are not limited.
+	ChangeRate *ChangeRatePolicy
+	// Guardrails limits how much of a zone a single change may delete or disable. If nil, changes are not limited.
+	Guardrails *Guardrails
+	// Clock is used for all time-dependent behaviour, such as delays between retries. If nil, the system clock is
+	// used.
+	Clock Clock
+	// Rand is used to add jitter to delays. If nil, the math/rand package's global source is used.
+	Rand Random
+
+	mu          sync.Mutex
+	connection  *Connection
+	subscribers map[chan Event]struct{}
+	rateLimit   RateLimitState
+	changes     map[string][]time.Time
 }
 
 // PingResponse is the API response to a ping request, containing the time the request was sent.
@@ -45,13 +80,13 @@ type PingResponse struct {
 
 // Ping sends a ping request to the API. It does not require authentication.
 func (c *Client) Ping(ctx context.Context) (*PingResponse, error) {
-	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("ping/%d", time.Now().Unix()), nil)
+	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("ping/%d", c.now().Unix()), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	response := &PingResponse{}
-	return response, json.Unmarshal(*res.Response, response)
+	return response, c.codec().Unmarshal(*res.Response, response)
 }
 
 // UserDataResponse is the API response to a user data request, describing the current user and access levels.
@@ -77,7 +112,7 @@ func (c *Client) UserData(ctx context.Context) (*UserDataResponse, error) {
 	}
 
 	response := &UserDataResponse{}
-	return response, json.Unmarshal(*res.Response, response)
+	return response, c.codec().Unmarshal(*res.Response, response)
 }
 
 // AccessLevel describes a level of access to a domain.
@@ -92,14 +127,24 @@ const (
 )
 
 // Domains lists all domains accessible by the current user, and gives the access level to each.
-func (c *Client) Domains(ctx context.Context) (map[string]AccessLevel, error) {
+func (c *Client) Domains(ctx context.Context) (DomainAccess, error) {
 	res, err := c.request(ctx, http.MethodGet, "domains", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	response := make(map[string]AccessLevel)
-	return response, json.Unmarshal(*res.Response, &response)
+	response := make(DomainAccess)
+	return response, c.codec().Unmarshal(*res.Response, &response)
+}
+
+// WritableDomains lists all domains the current user is able to modify records in.
+func (c *Client) WritableDomains(ctx context.Context) (DomainAccess, error) {
+	domains, err := c.Domains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return domains.Writable(), nil
 }
 
 // Record contains the basic details of a DNS record.
@@ -127,19 +172,22 @@ type ChangedRecord struct {
 	Deleted bool `json:"deleted,omitempty"`
 }
 
+// SOA describes the start of authority data for a domain.
+type SOA struct {
+	PrimaryNS    string `json:"primaryNS"`
+	AdminAddress string `json:"adminAddress"`
+	Serial       uint64 `json:"serial"`
+	Refresh      uint64 `json:"refresh"`
+	Retry        uint64 `json:"retry"`
+	Expire       uint64 `json:"expire"`
+	MinTTL       uint64 `json:"min_ttl"`
+}
+
 // RecordsResponse lists all records for a domain, as well as the NS and SOA data for it.
 type RecordsResponse struct {
 	Records []ExistingRecord `json:"records"`
 	HasNS   bool             `json:"hasNS"`
-	Soa     struct {
-		PrimaryNS    string `json:"primaryNS"`
-		AdminAddress string `json:"adminAddress"`
-		Serial       uint64 `json:"serial"`
-		Refresh      uint64 `json:"refresh"`
-		Retry        uint64 `json:"retry"`
-		Expire       uint64 `json:"expire"`
-		MinTTL       uint64 `json:"min_ttl"`
-	} `json:"soa"`
+	Soa     SOA              `json:"soa"`
 }
 
 // Records retrieves all records associated with the specified domain.
@@ -149,8 +197,11 @@ func (c *Client) Records(ctx context.Context, domain string) (*RecordsResponse,
 		return nil, err
 	}
 
-	response := &RecordsResponse{}
-	return response, json.Unmarshal(*res.Response, response)
+	wire := &wireRecordsResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	return wire.response(), nil
 }
 
 // RecordOperation is an operation performed on a record when calling ModifyRecords.
@@ -190,27 +241,90 @@ type ModifyRecordsResponse struct {
 }
 
 // ModifyRecords performs one or more operations on the records of a domain, including adding, modifying and deleting.
+// The operations are checked against the client's Guardrails before they are sent.
 func (c *Client) ModifyRecords(ctx context.Context, domain string, operations ...RecordOperation) (*ModifyRecordsResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+	if err := c.checkGuardrails(ctx, domain, operations); err != nil {
+		return nil, err
+	}
+	if err := c.checkChangeRate(domain, changedRecords(operations)...); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/records", domain), modifyRecordsRequest(operations))
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireModifyRecordsResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	response := wire.response()
+
+	c.zoneChanged(domain, response.Serial)
+	return response, nil
+}
+
+// ErrConcurrentModification is returned by ModifyRecordsAtSerial and UpdateSOA if the domain's serial has changed.
+var ErrConcurrentModification = errors.New("domain was modified concurrently")
+
+// ModifyRecordsAtSerial performs the same operations as ModifyRecords, but only if the domain's current SOA serial
+// matches expectedSerial. If the serial differs, ErrConcurrentModification is returned and no changes are made.
+// This prevents changes based on a stale view of the domain from overwriting changes made by someone else.
+//
+// The serial is checked by the client immediately before the changes are submitted, so there remains a small window
+// in which a concurrent change will not be detected. If ctx is cancelled after the serial has been checked, the
+// changes are not submitted.
+func (c *Client) ModifyRecordsAtSerial(ctx context.Context, domain string, expectedSerial uint64, operations ...RecordOperation) (*ModifyRecordsResponse, error) {
+	current, err := c.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSerial(expectedSerial, current.Soa.Serial); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.ModifyRecords(ctx, domain, operations...)
+}
+
+// checkSerial returns an error wrapping ErrConcurrentModification unless current is the expected serial. Serials are
+// compared using RFC 1982 serial number arithmetic, so the error can say whether the domain has moved on since the
+// expected serial was read.
+func checkSerial(expected, current uint64) error {
+	result, ok := CompareSerials(current, expected)
+	switch {
+	case ok && result == 0:
+		return nil
+	case ok && result > 0:
+		return fmt.Errorf("%w: expected serial %d but found newer serial %d", ErrConcurrentModification, expected, current)
+	default:
+		return fmt.Errorf("%w: expected serial %d but found %d", ErrConcurrentModification, expected, current)
+	}
+}
+
+// modifyRecordsRequest builds the request body for ModifyRecords. The operations are already encoded, so they are
+// converted in place to json.RawMessage rather than being copied.
+func modifyRecordsRequest(operations []RecordOperation) apiRequest {
 	records := make([]json.RawMessage, len(operations))
 	for i := range operations {
 		records[i] = json.RawMessage(operations[i])
 	}
 
-	r := apiRequest{
-		Data: struct {
+	return apiRequest{
+		Data: &struct {
 			Records []json.RawMessage `json:"records"`
 		}{
 			Records: records,
 		},
 	}
-
-	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/records", domain), r)
-	if err != nil {
-		return nil, err
-	}
-
-	response := &ModifyRecordsResponse{}
-	return response, json.Unmarshal(*res.Response, response)
 }
 
 // FindRecordsResponse lists all records for a domain that match provided search terms.
@@ -231,8 +345,48 @@ func (c *Client) NamedRecords(ctx context.Context, domain, recordName, recordTyp
 		return nil, err
 	}
 
-	response := &FindRecordsResponse{}
-	return response, json.Unmarshal(*res.Response, response)
+	wire := &wireFindRecordsResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	return wire.response(), nil
+}
+
+// RecordsOpts restricts the records returned by FilteredRecords.
+type RecordsOpts struct {
+	// Name limits the results to records with the given name. The filtering is performed by the API.
+	Name string
+	// Type limits the results to records of the given type. If Name is also given the filtering is performed by
+	// the API, otherwise all records are retrieved and filtered by the client.
+	Type string
+}
+
+// FilteredRecords retrieves the records of a domain that match the given options, fetching as little as possible.
+func (c *Client) FilteredRecords(ctx context.Context, domain string, opts RecordsOpts) ([]ExistingRecord, error) {
+	if opts.Name != "" {
+		res, err := c.NamedRecords(ctx, domain, opts.Name, opts.Type)
+		if err != nil {
+			return nil, err
+		}
+		return res.Records, nil
+	}
+
+	res, err := c.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Type == "" {
+		return res.Records, nil
+	}
+
+	var records []ExistingRecord
+	for i := range res.Records {
+		if strings.EqualFold(res.Records[i].Type, opts.Type) {
+			records = append(records, res.Records[i])
+		}
+	}
+	return records, nil
 }
 
 // DeletedNamedRecordsResponse describes the result of deleting named records
@@ -244,6 +398,13 @@ type DeletedNamedRecordsResponse struct {
 // DeleteNamedRecords deletes records with the given name under the specified domain.
 // recordType may be left blank to match all record types.
 func (c *Client) DeleteNamedRecords(ctx context.Context, domain, recordName, recordType string) (*DeletedNamedRecordsResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+	if err := c.checkChangeRate(domain, recordByName(recordName, recordType)); err != nil {
+		return nil, err
+	}
+
 	res, err := c.request(
 		ctx,
 		http.MethodDelete,
@@ -254,43 +415,173 @@ func (c *Client) DeleteNamedRecords(ctx context.Context, domain, recordName, rec
 		return nil, err
 	}
 
-	response := &DeletedNamedRecordsResponse{}
-	return response, json.Unmarshal(*res.Response, response)
+	wire := &wireDeletedNamedRecordsResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	response := wire.response()
+
+	if response.Deleted > 0 {
+		c.zoneChanged(domain, response.Serial)
+	}
+	return response, nil
 }
 
+// request performs a request to the API using the client's authenticator.
 func (c *Client) request(ctx context.Context, method string, route string, body interface{}) (*apiResponse, error) {
-	var reader io.Reader = nil
+	return c.requestWithAuth(ctx, c.Authenticator, method, route, body)
+}
+
+// requestWithAuth performs a request to the API using the given authenticator, retrying according to the client's
+// RetryPolicy and RateLimitRetries. If the authenticator is a RefreshableAuthenticator, it is refreshed before the
+// request if it has no credentials, and the request is retried once after refreshing if it is rejected.
+func (c *Client) requestWithAuth(ctx context.Context, auth ClientAuthenticator, method string, route string, body interface{}) (*apiResponse, error) {
+	refreshable, _ := auth.(RefreshableAuthenticator)
+	refreshed := false
+	if refreshable != nil && !refreshable.Authenticated() {
+		if err := refreshable.Refresh(ctx, c); err != nil {
+			return nil, err
+		}
+		refreshed = true
+	}
+
+	rateLimitRetries := 0
+	for attempt := 1; ; attempt++ {
+		res, status, err := c.attempt(ctx, auth, method, route, body)
+		if ctx.Err() != nil {
+			return res, err
+		}
+
+		if refreshable != nil && !refreshed && (status == http.StatusUnauthorized || status == http.StatusForbidden) {
+			if err := refreshable.Refresh(ctx, c); err != nil {
+				return nil, err
+			}
+
+			refreshed = true
+			attempt--
+			continue
+		}
+
+		var rateLimitErr *RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			if rateLimitRetries >= c.RateLimitRetries {
+				return res, err
+			}
+
+			delay, ok := c.rateLimitDelay(ctx, rateLimitErr)
+			if !ok || !c.sleep(ctx, delay) {
+				return nil, err
+			}
+
+			rateLimitRetries++
+			attempt--
+			continue
+		}
+
+		if !c.Retry.shouldRetry(method, attempt, status, err) {
+			return res, err
+		}
+
+		delay := c.Retry.backoff(attempt, c.random())
+		if c.hasSubscribers() {
+			c.emit(&RetryScheduledEvent{Time: c.now(), Method: method, Route: route, Attempt: attempt, Delay: delay, Err: err})
+		}
+
+		if !c.sleep(ctx, delay) {
+			return nil, err
+		}
+	}
+}
+
+// attempt makes a single request to the API, emitting events if there are subscribers.
+func (c *Client) attempt(ctx context.Context, auth ClientAuthenticator, method string, route string, body interface{}) (*apiResponse, int, error) {
+	if !c.hasSubscribers() {
+		return c.do(ctx, auth, method, route, body)
+	}
+
+	start := c.now()
+	c.emit(&RequestStartedEvent{Time: start, Method: method, Route: route})
+	res, status, err := c.do(ctx, auth, method, route, body)
+	end := c.now()
+	c.emit(&RequestCompletedEvent{Time: end, Method: method, Route: route, Duration: end.Sub(start), Err: err})
+	return res, status, err
+}
+
+// do performs a single request to the API, returning the decoded response and the HTTP status code. The status is
+// zero if no response was received.
+func (c *Client) do(ctx context.Context, auth ClientAuthenticator, method string, route string, body interface{}) (*apiResponse, int, error) {
+	var reader io.Reader
 	if body != nil {
-		b, err := json.Marshal(body)
+		b, err := c.codec().Marshal(body)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		// A *bytes.Reader lets the request set ContentLength and GetBody, so the body can be replayed.
 		reader = bytes.NewReader(b)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("https://%s/%s/%s", apiHost, apiVersion, route), reader)
+	req, err := http.NewRequestWithContext(ctx, method, c.url(route), reader)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	if c.Authenticator != nil {
-		c.Authenticator.AddHeaders(req)
+	if auth != nil {
+		auth.AddHeaders(req)
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.httpClient().Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusTooManyRequests {
+		return nil, res.StatusCode, c.rateLimited(method, route, res)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(res.Body); err != nil {
+		return nil, res.StatusCode, err
+	}
+
 	response := &apiResponse{}
-	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
-		return nil, err
+	if err := c.codec().Unmarshal(buf.Bytes(), response); err != nil {
+		return nil, res.StatusCode, newResponseError(res.StatusCode, buf.Bytes(), err)
 	}
 
 	if response.Error != nil {
-		return nil, fmt.Errorf("API error: %s", *response.Error)
+		return nil, res.StatusCode, &APIError{
+			Message:    *response.Error,
+			ErrorData:  response.ErrorData,
+			ResponseId: response.ResponseId,
+			Method:     response.Method,
+			StatusCode: res.StatusCode,
+		}
 	}
 
-	return response, nil
+	if res.StatusCode >= 400 {
+		return nil, res.StatusCode, newResponseError(res.StatusCode, buf.Bytes(), nil)
+	}
+
+	if response.Response == nil {
+		return nil, res.StatusCode, newResponseError(res.StatusCode, buf.Bytes(), errors.New("response contained no data"))
+	}
+
+	return response, res.StatusCode, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+func (c *Client) url(route string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(base, "/"), route)
 }
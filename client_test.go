@@ -0,0 +1,1714 @@
+package mydnshost_go_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testServer starts a server that responds to every request with the given response body, and returns a client
+// configured to use it. The most recent request received is stored in lastRequest, with its body buffered so that
+// it can be read with requestBody.
+func testServer(t *testing.T, response string, lastRequest **http.Request) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lastRequest != nil {
+			body, _ := ioutil.ReadAll(r.Body)
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			*lastRequest = r
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Client{BaseURL: server.URL + "/1.0/"}
+}
+
+// requestBody returns the body of a request stored by testServer, without the trailing newline written by the
+// encoder.
+func requestBody(t *testing.T, req *http.Request) string {
+	t.Helper()
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("unable to read request body: %v", err)
+	}
+	return string(body)
+}
+
+func TestClient_BaseURL(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"time":"123"}}`, &req)
+
+	res, err := c.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if res.Time != "123" {
+		t.Errorf("Ping() time = %q, want %q", res.Time, "123")
+	}
+	if !strings.HasPrefix(req.URL.Path, "/1.0/ping/") {
+		t.Errorf("request path = %q, want /1.0/ping/...", req.URL.Path)
+	}
+}
+
+func TestClient_DefaultBaseURL(t *testing.T) {
+	c := &Client{}
+	if got, want := c.url("domains"), "https://api.mydnshost.co.uk/1.0/domains"; got != want {
+		t.Errorf("url() = %q, want %q", got, want)
+	}
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	c := testServer(t, `{"response":{"serial":5,"changed":[]}}`, nil)
+	events, cancel := c.Subscribe(10)
+
+	if _, err := c.ModifyRecords(context.Background(), "example.com", DeleteRecord(1)); err != nil {
+		t.Fatalf("ModifyRecords() error = %v", err)
+	}
+	cancel()
+
+	var got []Event
+	for e := range events {
+		got = append(got, e)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("received %d events, want 3: %v", len(got), got)
+	}
+	if e, ok := got[0].(*RequestStartedEvent); !ok || e.Method != http.MethodPost || e.Route != "domains/example.com/records" {
+		t.Errorf("first event = %#v, want RequestStartedEvent", got[0])
+	}
+	if e, ok := got[1].(*RequestCompletedEvent); !ok || e.Err != nil {
+		t.Errorf("second event = %#v, want successful RequestCompletedEvent", got[1])
+	}
+	if e, ok := got[2].(*ZoneChangedEvent); !ok || e.Domain != "example.com" || e.Serial != 5 {
+		t.Errorf("third event = %#v, want ZoneChangedEvent", got[2])
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"response":{"example.com":"owner"}}`))
+	}))
+	defer server.Close()
+
+	c := &Client{
+		BaseURL: server.URL,
+		Retry:   &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	domains, err := c.Domains(context.Background())
+	if err != nil {
+		t.Fatalf("Domains() error = %v", err)
+	}
+	if attempts != 3 || domains["example.com"] != LevelOwner {
+		t.Errorf("Domains() = %v after %d attempts, want success after 3", domains, attempts)
+	}
+
+	attempts = 0
+	if _, err := c.ModifyRecords(context.Background(), "example.com", DeleteRecord(1)); err == nil {
+		t.Errorf("ModifyRecords() should not have been retried")
+	}
+	if attempts != 1 {
+		t.Errorf("ModifyRecords() made %d attempts, want 1", attempts)
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"response":{"serial":2,"changed":[]}}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	_, err := c.ModifyRecords(context.Background(), "example.com", DeleteRecord(1))
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("ModifyRecords() error = %v, want *RateLimitError", err)
+	}
+	if state := c.RateLimit(); state.Count != 1 || state.LimitedAt.IsZero() {
+		t.Errorf("RateLimit() = %+v, want one recorded limit", state)
+	}
+
+	attempts = 0
+	c.RateLimitRetries = 1
+	c.Clock = instantClock{}
+	if _, err := c.ModifyRecords(context.Background(), "example.com", DeleteRecord(1)); err != nil {
+		t.Errorf("ModifyRecords() with RateLimitRetries error = %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("ModifyRecords() made %d attempts, want 2", attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"120":                           2 * time.Minute,
+		"-1":                            0,
+		"Sun, 13 Sep 2020 12:00:30 GMT": 30 * time.Second,
+		"Sun, 13 Sep 2020 11:00:00 GMT": 0,
+		"soon":                          0,
+	}
+
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+// instantClock is a Clock whose timers fire immediately.
+type instantClock struct{}
+
+func (instantClock) Now() time.Time {
+	return time.Now()
+}
+
+func (instantClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return ch
+}
+
+func TestClient_APIError(t *testing.T) {
+	c := testServer(t, `{"respid":"abc","method":"POST","error":"Example error","errorData":{"0":"Invalid content"}}`, nil)
+
+	_, err := c.ModifyRecords(context.Background(), "example.com", DeleteRecord(1))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("ModifyRecords() error = %v, want *APIError", err)
+	}
+
+	if apiErr.Message != "Example error" || apiErr.ResponseId != "abc" || apiErr.Method != "POST" || apiErr.ErrorData["0"] != "Invalid content" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if got, want := err.Error(), "API error: Example error"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if hints := apiErr.Hints(); len(hints) != 1 || hints[0] != (ErrorHint{Operation: 0, Message: "Invalid content"}) {
+		t.Errorf("Hints() = %+v, want operation 0 invalid", hints)
+	}
+}
+
+func TestAPIError_Hints(t *testing.T) {
+	err := &APIError{Message: "Error with records.", ErrorData: map[string]string{
+		"10":      "Invalid name",
+		"zone":    "Unknown domain",
+		"2":       "Invalid content",
+		"content": "Content is required",
+		"-1":      "Unexpected",
+	}}
+
+	want := []string{
+		"operation 2: Invalid content",
+		"operation 10: Invalid name",
+		"-1: Unexpected",
+		"content: Content is required",
+		"zone: Unknown domain",
+	}
+	hints := err.Hints()
+	if len(hints) != len(want) {
+		t.Fatalf("Hints() = %v, want %q", hints, want)
+	}
+	for i := range want {
+		if got := hints[i].String(); got != want[i] {
+			t.Errorf("Hints()[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+	if hints[0].Operation != 2 || hints[0].Field != "" || hints[3].Operation != -1 || hints[3].Field != "content" {
+		t.Errorf("Hints() = %+v, want operations and fields separated", hints)
+	}
+
+	if hints := (&APIError{Message: "Example error"}).Hints(); len(hints) != 0 {
+		t.Errorf("Hints() without ErrorData = %v, want none", hints)
+	}
+}
+
+func TestClient_ResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html>Bad Gateway</html>"))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	_, err := c.Domains(context.Background())
+
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) {
+		t.Fatalf("Domains() error = %v, want *ResponseError", err)
+	}
+	if responseErr.StatusCode != http.StatusBadGateway || string(responseErr.Body) != "<html>Bad Gateway</html>" || responseErr.Err == nil {
+		t.Errorf("ResponseError = %+v", responseErr)
+	}
+}
+
+func TestClient_Login(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"session":"abc123"}}`, &req)
+
+	session, err := c.Login(context.Background(), "user@example.com", "hunter2")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if session.SessionID != "abc123" {
+		t.Errorf("Login() session = %q, want %q", session.SessionID, "abc123")
+	}
+	if user, pass, ok := req.BasicAuth(); !ok || user != "user@example.com" || pass != "hunter2" {
+		t.Errorf("Login() sent credentials %q, %q", user, pass)
+	}
+
+	c.Authenticator = session
+	if _, err := c.UserData(context.Background()); err != nil {
+		t.Fatalf("UserData() error = %v", err)
+	}
+	if got := req.Header.Get("X-Session-ID"); got != "abc123" {
+		t.Errorf("UserData() sent session header %q, want %q", got, "abc123")
+	}
+}
+
+func TestClient_LoginTwoFactor(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"error":"2FA key required."}`, &req)
+
+	_, err := c.Login(context.Background(), "user@example.com", "hunter2")
+	if !errors.Is(err, ErrTwoFactorRequired) {
+		t.Errorf("Login() error = %v, want ErrTwoFactorRequired", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("Login() error = %v, want *APIError", err)
+	}
+
+	_, err = c.LoginWithCode(context.Background(), "user@example.com", "hunter2", "123456")
+	if !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("LoginWithCode() error = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if got := req.Header.Get("X-2FA-Key"); got != "123456" {
+		t.Errorf("LoginWithCode() sent code %q, want %q", got, "123456")
+	}
+}
+
+func TestClient_LoginAuthenticator(t *testing.T) {
+	logins := 0
+	valid := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/session"):
+			logins++
+			valid = fmt.Sprintf("session-%d", logins)
+			_, _ = fmt.Fprintf(w, `{"response":{"session":%q}}`, valid)
+		case r.Header.Get("X-Session-ID") != valid:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"Session expired"}`))
+		default:
+			_, _ = w.Write([]byte(`{"response":{"example.com":"owner"}}`))
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{
+		BaseURL:       server.URL,
+		Authenticator: &LoginAuthenticator{Email: "user@example.com", Password: "hunter2"},
+	}
+
+	if _, err := c.Domains(context.Background()); err != nil {
+		t.Fatalf("Domains() error = %v", err)
+	}
+	if logins != 1 {
+		t.Errorf("Domains() logged in %d times, want 1", logins)
+	}
+
+	valid = ""
+	if _, err := c.Domains(context.Background()); err != nil {
+		t.Fatalf("Domains() after session expiry error = %v", err)
+	}
+	if logins != 2 {
+		t.Errorf("Domains() after session expiry logged in %d times, want 2", logins)
+	}
+}
+
+func TestClient_Freeze(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"serial":2,"changed":[]}}`, &req)
+	c.Freeze = &FreezePolicy{Domains: []string{"Example.com."}}
+
+	if _, err := c.ModifyRecords(context.Background(), "example.com", DeleteRecord(1)); !errors.Is(err, ErrFrozen) {
+		t.Errorf("ModifyRecords() error = %v, want ErrFrozen", err)
+	}
+	if _, err := c.DeleteNamedRecords(context.Background(), "example.com", "www", ""); !errors.Is(err, ErrFrozen) {
+		t.Errorf("DeleteNamedRecords() error = %v, want ErrFrozen", err)
+	}
+	if _, err := c.UpdateDomain(context.Background(), "example.com", DomainSettings{Disabled: boolPtr(true)}); !errors.Is(err, ErrFrozen) {
+		t.Errorf("UpdateDomain() error = %v, want ErrFrozen", err)
+	}
+	if _, err := c.ImportZone(context.Background(), "example.com", "", ImportZoneOpts{}); !errors.Is(err, ErrFrozen) {
+		t.Errorf("ImportZone() error = %v, want ErrFrozen", err)
+	}
+	if _, err := c.UpdateRecord(context.Background(), "example.com", 1, Record{}); !errors.Is(err, ErrFrozen) {
+		t.Errorf("UpdateRecord() error = %v, want ErrFrozen", err)
+	}
+	if _, err := c.DeleteRecordByID(context.Background(), "example.com", 1); !errors.Is(err, ErrFrozen) {
+		t.Errorf("DeleteRecordByID() error = %v, want ErrFrozen", err)
+	}
+	if _, err := c.UpdateSOA(context.Background(), "example.com", SOA{PrimaryNS: "ns1.example.com.", AdminAddress: "admin.example.com."}); !errors.Is(err, ErrFrozen) {
+		t.Errorf("UpdateSOA() error = %v, want ErrFrozen", err)
+	}
+	if req != nil {
+		t.Errorf("frozen domain was sent a request: %s %s", req.Method, req.URL)
+	}
+
+	// Resyncing doesn't change the zone, so is allowed during a freeze.
+	if err := c.SyncDomain(context.Background(), "example.com"); err != nil {
+		t.Errorf("SyncDomain() of frozen domain error = %v", err)
+	}
+	if _, err := c.ModifyRecords(WithFreezeOverride(context.Background()), "example.com", DeleteRecord(1)); err != nil {
+		t.Errorf("ModifyRecords() with override error = %v", err)
+	}
+	if _, err := c.ModifyRecords(context.Background(), "example.org", DeleteRecord(1)); err != nil {
+		t.Errorf("ModifyRecords() of unfrozen domain error = %v", err)
+	}
+}
+
+func TestClient_Guardrails(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"response":{"records":[
+				{"id":"1","name":"","type":"NS","content":"ns1.mydnshost.co.uk","ttl":"86400"},
+				{"id":"2","name":"www","type":"A","content":"192.0.2.1","ttl":"300"},
+				{"id":"3","name":"api","type":"A","content":"192.0.2.2","ttl":"300"},
+				{"id":"4","name":"mail","type":"A","content":"192.0.2.3","ttl":"300"}
+			],"soa":{"serial":"1"}}}`))
+		case strings.HasSuffix(r.URL.Path, "/import"):
+			_, _ = w.Write([]byte(`{"response":{"serial":"2"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"response":{"serial":"2","changed":[]}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/", Guardrails: &Guardrails{MaxDeletePercent: 50, MaxOperations: 3}}
+
+	disabled := true
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		operations   []RecordOperation
+		wantExceeded bool
+		wantRequests []string
+	}{
+		{"within limits", context.Background(), []RecordOperation{DeleteRecord(2), DeleteRecord(3)}, false,
+			[]string{"GET /1.0/domains/example.com/records", "POST /1.0/domains/example.com/records"}},
+		{"too many deletes", context.Background(), []RecordOperation{DeleteRecord(2), DeleteRecord(3), DeleteRecord(4)}, true,
+			[]string{"GET /1.0/domains/example.com/records"}},
+		{"too many disables", context.Background(), []RecordOperation{
+			ModifyRecord(2, Record{Disabled: &disabled}),
+			ModifyRecord(3, Record{Disabled: &disabled}),
+			ModifyRecord(4, Record{Disabled: &disabled}),
+		}, true, []string{"GET /1.0/domains/example.com/records"}},
+		{"too many operations", context.Background(), []RecordOperation{
+			CreateRecord(Record{Name: "a", Type: "A", Content: "192.0.2.10"}),
+			CreateRecord(Record{Name: "b", Type: "A", Content: "192.0.2.11"}),
+			CreateRecord(Record{Name: "c", Type: "A", Content: "192.0.2.12"}),
+			CreateRecord(Record{Name: "d", Type: "A", Content: "192.0.2.13"}),
+		}, true, nil},
+		{"override", WithGuardrailOverride(context.Background()), []RecordOperation{DeleteRecord(1), DeleteRecord(2), DeleteRecord(3), DeleteRecord(4)}, false,
+			[]string{"POST /1.0/domains/example.com/records"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			_, err := c.ModifyRecords(tt.ctx, "example.com", tt.operations...)
+			if got := errors.Is(err, ErrGuardrailExceeded); got != tt.wantExceeded {
+				t.Errorf("ModifyRecords() error = %v, want exceeded %v", err, tt.wantExceeded)
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", requests, tt.wantRequests)
+			}
+		})
+	}
+
+	t.Run("import", func(t *testing.T) {
+		requests = nil
+		if _, err := c.ImportZone(context.Background(), "example.com", "www 300 IN A 192.0.2.1\n", ImportZoneOpts{}); !errors.Is(err, ErrGuardrailExceeded) {
+			t.Errorf("ImportZone() replacing most records error = %v, want ErrGuardrailExceeded", err)
+		}
+		if want := []string{"GET /1.0/domains/example.com/records"}; !reflect.DeepEqual(requests, want) {
+			t.Errorf("requests = %q, want %q", requests, want)
+		}
+
+		requests = nil
+		zone := "@ 86400 IN NS ns1.mydnshost.co.uk.\nwww 300 IN A 192.0.2.1\napi 300 IN A 192.0.2.2\n"
+		if _, err := c.ImportZone(context.Background(), "example.com", zone, ImportZoneOpts{}); err != nil {
+			t.Errorf("ImportZone() removing one record error = %v", err)
+		}
+		if want := []string{"GET /1.0/domains/example.com/records", "POST /1.0/domains/example.com/import"}; !reflect.DeepEqual(requests, want) {
+			t.Errorf("requests = %q, want %q", requests, want)
+		}
+
+		if _, err := c.ImportZone(context.Background(), "example.com", "$INCLUDE other.zone\n", ImportZoneOpts{}); err == nil {
+			t.Errorf("ImportZone() of a zone that can't be checked succeeded")
+		}
+	})
+}
+
+func TestDomainSettings_Encode(t *testing.T) {
+	alias := ""
+	body, err := StandardCodec{}.Marshal(apiRequest{Data: DomainSettings{Disabled: boolPtr(false), AliasOf: &alias}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if want := `{"data":{"disabled":false,"aliasof":""}}`; string(body) != want {
+		t.Errorf("Marshal() = %s, want %s", body, want)
+	}
+}
+
+func TestClient_DomainHooks(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "responses", "hooks.json"))
+	if err != nil {
+		t.Fatalf("unable to read fixture: %v", err)
+	}
+
+	var req *http.Request
+	c := testServer(t, string(data), &req)
+
+	hooks, err := c.DomainHooks(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("DomainHooks() error = %v", err)
+	}
+
+	if req.URL.Path != "/1.0/domains/example.com/hooks" {
+		t.Errorf("request path = %q, want /1.0/domains/example.com/hooks", req.URL.Path)
+	}
+	if len(hooks) != 2 || hooks[0].Id != 1 || !hooks[0].Disabled || hooks[1].URL != "https://ci.example.com/hooks/dns" {
+		t.Errorf("DomainHooks() = %+v, want hooks 1 and 2 in order", hooks)
+	}
+}
+
+func TestClient_DomainHooksEmpty(t *testing.T) {
+	c := testServer(t, `{"response":[]}`, nil)
+
+	hooks, err := c.DomainHooks(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("DomainHooks() error = %v", err)
+	}
+	if len(hooks) != 0 {
+		t.Errorf("DomainHooks() = %+v, want none", hooks)
+	}
+}
+
+func TestClient_CreateDomainHook(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"3","url":"https://example.com/hook","password":"secret","disabled":"false","created":"1594654800","lastused":"0"}}`, &req)
+
+	hook, err := c.CreateDomainHook(context.Background(), "example.com", "https://example.com/hook", "secret")
+	if err != nil {
+		t.Fatalf("CreateDomainHook() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com/hooks" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com/hooks", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"url":"https://example.com/hook","password":"secret"}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if hook.Id != 3 || hook.Disabled || hook.Created != 1594654800 {
+		t.Errorf("CreateDomainHook() = %+v, want enabled hook 3", hook)
+	}
+}
+
+func TestClient_UpdateDomainHook(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"3","url":"https://example.com/hook","disabled":"true","created":"1594654800","lastused":"0"}}`, &req)
+
+	hook, err := c.UpdateDomainHook(context.Background(), "example.com", 3, DomainHookSettings{Disabled: boolPtr(true)})
+	if err != nil {
+		t.Fatalf("UpdateDomainHook() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com/hooks/3" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com/hooks/3", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"disabled":true}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if hook.Id != 3 || !hook.Disabled {
+		t.Errorf("UpdateDomainHook() = %+v, want disabled hook 3", hook)
+	}
+}
+
+func TestClient_DeleteDomainHook(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"deleted":"true"}}`, &req)
+
+	if err := c.DeleteDomainHook(context.Background(), "example.com", 3); err != nil {
+		t.Fatalf("DeleteDomainHook() error = %v", err)
+	}
+
+	if req.Method != http.MethodDelete || req.URL.Path != "/1.0/domains/example.com/hooks/3" {
+		t.Errorf("request = %s %s, want DELETE /1.0/domains/example.com/hooks/3", req.Method, req.URL.Path)
+	}
+}
+
+func TestClient_TwoFactorDevices(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "responses", "2fa.json"))
+	if err != nil {
+		t.Fatalf("unable to read fixture: %v", err)
+	}
+
+	var req *http.Request
+	c := testServer(t, string(data), &req)
+
+	devices, err := c.TwoFactorDevices(context.Background())
+	if err != nil {
+		t.Fatalf("TwoFactorDevices() error = %v", err)
+	}
+
+	if req.URL.Path != "/1.0/users/self/2fa" {
+		t.Errorf("request path = %q, want /1.0/users/self/2fa", req.URL.Path)
+	}
+	if len(devices) != 2 || devices[0].Id != 3 || !devices[0].Active || devices[0].LastUsed != 1594654500 || devices[1].Description != "Backup phone" || devices[1].Active {
+		t.Errorf("TwoFactorDevices() = %+v, want devices 3 and 4 in order", devices)
+	}
+}
+
+func TestClient_TwoFactorDevicesEmpty(t *testing.T) {
+	c := testServer(t, `{"response":[]}`, nil)
+
+	devices, err := c.TwoFactorDevices(context.Background())
+	if err != nil {
+		t.Fatalf("TwoFactorDevices() error = %v", err)
+	}
+	if len(devices) != 0 {
+		t.Errorf("TwoFactorDevices() = %+v, want none", devices)
+	}
+}
+
+func TestClient_CreateTwoFactorDevice(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"5","description":"Laptop","type":"rfc6238","created":"1594654700","lastused":"0","active":"false","key":"JBSWY3DPEHPK3PXP"}}`, &req)
+
+	device, err := c.CreateTwoFactorDevice(context.Background(), "Laptop")
+	if err != nil {
+		t.Fatalf("CreateTwoFactorDevice() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/users/self/2fa" {
+		t.Errorf("request = %s %s, want POST /1.0/users/self/2fa", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"description":"Laptop","type":"rfc6238"}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if device.Id != 5 || device.Created != 1594654700 || device.Active || device.Key != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("CreateTwoFactorDevice() = %+v, want inactive device 5 with its key", device)
+	}
+}
+
+func TestClient_VerifyTwoFactorDevice(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"success":"Key verified"}}`, &req)
+
+	if err := c.VerifyTwoFactorDevice(context.Background(), 5, "123456"); err != nil {
+		t.Fatalf("VerifyTwoFactorDevice() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/users/self/2fa/5/verify" {
+		t.Errorf("request = %s %s, want POST /1.0/users/self/2fa/5/verify", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"code":"123456"}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+}
+
+func TestClient_DeleteTwoFactorDevice(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"deleted":"true"}}`, &req)
+
+	if err := c.DeleteTwoFactorDevice(context.Background(), 5); err != nil {
+		t.Fatalf("DeleteTwoFactorDevice() error = %v", err)
+	}
+
+	if req.Method != http.MethodDelete || req.URL.Path != "/1.0/users/self/2fa/5" {
+		t.Errorf("request = %s %s, want DELETE /1.0/users/self/2fa/5", req.Method, req.URL.Path)
+	}
+}
+
+func TestClient_DomainStats(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"stats":{"A":[{"time":1600000000,"value":12},{"time":1600003600,"value":7.5}]}}}`, &req)
+
+	res, err := c.DomainStats(context.Background(), "example.com", DomainStatsOpts{Type: "queries-per-rrtype", Period: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("DomainStats() error = %v", err)
+	}
+
+	if req.URL.Path != "/1.0/domains/example.com/stats" || req.URL.Query().Get("type") != "queries-per-rrtype" || req.URL.Query().Get("time") != "86400" {
+		t.Errorf("request = %s, want /1.0/domains/example.com/stats?time=86400&type=queries-per-rrtype", req.URL)
+	}
+	if len(res.Stats["A"]) != 2 || res.Stats["A"][1].Value != 7.5 {
+		t.Errorf("DomainStats() = %+v, want two A datapoints", res.Stats)
+	}
+}
+
+func TestAccessWatcher(t *testing.T) {
+	responses := map[string]string{
+		"/1.0/domains":                    `{"response":{"example.com":"owner","example.org":"read"}}`,
+		"/1.0/domains/example.com/access": `{"response":{"access":{"user@example.com":"owner"}}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &Client{BaseURL: server.URL + "/1.0"}
+	w := &AccessWatcher{Client: c, Users: true}
+
+	if changes, err := w.Check(context.Background()); err != nil || len(changes) != 0 {
+		t.Fatalf("first Check() = %v, %v, want no changes", changes, err)
+	}
+
+	responses["/1.0/domains"] = `{"response":{"example.com":"owner","example.net":"write"}}`
+	responses["/1.0/domains/example.com/access"] = `{"response":{"access":{"user@example.com":"owner","colleague@example.com":"admin"}}}`
+
+	changes, err := w.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := []AccessChangedEvent{
+		{Domain: "example.net", Old: LevelNone, New: LevelWrite},
+		{Domain: "example.org", Old: LevelRead, New: LevelNone},
+		{Domain: "example.com", User: "colleague@example.com", Old: LevelNone, New: LevelAdmin},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Check() = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		changes[i].Time = time.Time{}
+		if changes[i] != want[i] {
+			t.Errorf("Check()[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestAccessWatcher_StartStop(t *testing.T) {
+	checked := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"response":{"example.com":"owner"}}`))
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	w := &AccessWatcher{Client: &Client{BaseURL: server.URL + "/1.0"}, Interval: time.Hour}
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := w.Start(context.Background()); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("second Start() error = %v, want ErrAlreadyStarted", err)
+	}
+
+	<-checked
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.Stop(ctx); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+	if err := w.Start(context.Background()); err != nil {
+		t.Errorf("Start() after Stop() error = %v", err)
+	}
+	if err := w.Stop(ctx); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestClient_ExportZone(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"zone":"$ORIGIN example.com.\nwww IN CNAME example.com.\n"}}`, &req)
+
+	zone, err := c.ExportZone(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ExportZone() error = %v", err)
+	}
+
+	if req.URL.Path != "/1.0/domains/example.com/export" {
+		t.Errorf("request path = %q, want /1.0/domains/example.com/export", req.URL.Path)
+	}
+	if !strings.HasPrefix(zone, "$ORIGIN example.com.\n") {
+		t.Errorf("ExportZone() = %q, want zone file", zone)
+	}
+}
+
+func TestClient_Record(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"12","name":"www","type":"CNAME","content":"example.com","ttl":"300","disabled":"false"}}`, &req)
+
+	record, err := c.Record(context.Background(), "example.com", 12)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	if req.URL.Path != "/1.0/domains/example.com/records/12" {
+		t.Errorf("request path = %q, want /1.0/domains/example.com/records/12", req.URL.Path)
+	}
+	if record.Id != 12 || record.Name != "www" || record.TTL != 300 || record.Disabled == nil || *record.Disabled {
+		t.Errorf("Record() = %+v, want record 12", record)
+	}
+}
+
+func TestClient_FilteredRecords(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"response":{"records":[
+			{"id":"1","name":"","type":"MX","content":"mx1.example.com","ttl":"3600","priority":"10"},
+			{"id":"2","name":"www","type":"A","content":"192.0.2.1","ttl":"300"},
+			{"id":"3","name":"api","type":"a","content":"192.0.2.2","ttl":"300"}
+		],"soa":{"serial":"1"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	tests := []struct {
+		name        string
+		opts        RecordsOpts
+		wantRequest string
+		wantIds     []int
+	}{
+		{"all", RecordsOpts{}, "/1.0/domains/example.com/records", []int{1, 2, 3}},
+		{"type", RecordsOpts{Type: "A"}, "/1.0/domains/example.com/records", []int{2, 3}},
+		// The API filters named requests, so their results are returned as given.
+		{"name", RecordsOpts{Name: "www"}, "/1.0/domains/example.com/record/www", []int{1, 2, 3}},
+		{"name and type", RecordsOpts{Name: "www", Type: "A"}, "/1.0/domains/example.com/record/www/A", []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			records, err := c.FilteredRecords(context.Background(), "example.com", tt.opts)
+			if err != nil {
+				t.Fatalf("FilteredRecords() error = %v", err)
+			}
+
+			// Filters are sent in the path; no query parameters are used.
+			if want := []string{tt.wantRequest}; !reflect.DeepEqual(requests, want) {
+				t.Errorf("requests = %q, want %q", requests, want)
+			}
+			var ids []int
+			for i := range records {
+				ids = append(ids, records[i].Id)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIds) {
+				t.Errorf("FilteredRecords() returned records %v, want %v", ids, tt.wantIds)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateRecord(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":12,"name":"www","type":"A","content":"192.0.2.2","ttl":300,"serial":"2020091304"}}`, &req)
+	events, cancel := c.Subscribe(8)
+	defer cancel()
+
+	res, err := c.UpdateRecord(context.Background(), "example.com", 12, Record{Name: "www", Type: "A", Content: "192.0.2.2", TTL: 300})
+	if err != nil {
+		t.Fatalf("UpdateRecord() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com/records/12" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com/records/12", req.Method, req.URL.Path)
+	}
+	if res.Id != 12 || res.Content != "192.0.2.2" || res.Serial != 2020091304 {
+		t.Errorf("UpdateRecord() = %+v, want record 12 at serial 2020091304", res)
+	}
+
+	for {
+		if e, ok := (<-events).(*ZoneChangedEvent); ok {
+			if e.Domain != "example.com" || e.Serial != 2020091304 {
+				t.Errorf("ZoneChangedEvent = %+v, want example.com at serial 2020091304", e)
+			}
+			break
+		}
+	}
+}
+
+func TestClient_DeleteRecordByID(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"deleted":"true","serial":2020091305}}`, &req)
+
+	res, err := c.DeleteRecordByID(context.Background(), "example.com", 12)
+	if err != nil {
+		t.Fatalf("DeleteRecordByID() error = %v", err)
+	}
+
+	if req.Method != http.MethodDelete || req.URL.Path != "/1.0/domains/example.com/records/12" {
+		t.Errorf("request = %s %s, want DELETE /1.0/domains/example.com/records/12", req.Method, req.URL.Path)
+	}
+	if !res.Deleted || res.Serial != 2020091305 {
+		t.Errorf("DeleteRecordByID() = %+v, want deleted at serial 2020091305", res)
+	}
+}
+
+// steppedClock is a Clock whose time only changes when it is advanced, and whose timers fire immediately after
+// advancing the clock by the timer's duration.
+type steppedClock struct {
+	now *time.Time
+}
+
+func (c steppedClock) Now() time.Time {
+	return *c.now
+}
+
+func (c steppedClock) After(d time.Duration) <-chan time.Time {
+	*c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- *c.now
+	return ch
+}
+
+func TestLoginAuthenticator_Renewal(t *testing.T) {
+	logins := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/session") {
+			logins++
+			if logins == 4 {
+				cancel()
+			}
+			_, _ = fmt.Fprintf(w, `{"response":{"session":"session-%d"}}`, logins)
+			return
+		}
+		_, _ = w.Write([]byte(`{"response":{"example.com":"owner"}}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC)
+	auth := &LoginAuthenticator{Email: "user@example.com", Password: "hunter2", Lifetime: 10 * time.Minute}
+	c := &Client{BaseURL: server.URL, Authenticator: auth, Clock: steppedClock{now: &now}}
+
+	if _, err := c.Domains(context.Background()); err != nil {
+		t.Fatalf("Domains() error = %v", err)
+	}
+	if want := now.Add(10 * time.Minute); !auth.Expiry().Equal(want) {
+		t.Errorf("Expiry() = %v, want %v", auth.Expiry(), want)
+	}
+
+	now = now.Add(9*time.Minute + 30*time.Second)
+	if _, err := c.Domains(context.Background()); err != nil {
+		t.Fatalf("Domains() near expiry error = %v", err)
+	}
+	if logins != 2 {
+		t.Errorf("Domains() near expiry logged in %d times, want 2", logins)
+	}
+
+	// KeepAlive should keep renewing the session until it is cancelled during the fourth login.
+	if err := auth.KeepAlive(ctx, c); !errors.Is(err, context.Canceled) {
+		t.Errorf("KeepAlive() error = %v, want context.Canceled", err)
+	}
+	if err := (&LoginAuthenticator{}).KeepAlive(context.Background(), c); err == nil {
+		t.Errorf("KeepAlive() without Lifetime error = nil, want error")
+	}
+}
+
+func TestLoginAuthenticator_ShortLifetime(t *testing.T) {
+	logins := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/session") {
+			logins++
+			if logins == 3 {
+				cancel()
+			}
+			_, _ = fmt.Fprintf(w, `{"response":{"session":"session-%d"}}`, logins)
+			return
+		}
+		_, _ = w.Write([]byte(`{"response":{"example.com":"owner"}}`))
+	}))
+	defer server.Close()
+
+	// The lifetime is shorter than the default renewal margin, so sessions are renewed halfway through instead.
+	start := time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC)
+	now := start
+	auth := &LoginAuthenticator{Email: "user@example.com", Password: "hunter2", Lifetime: 30 * time.Second}
+	c := &Client{BaseURL: server.URL, Authenticator: auth, Clock: steppedClock{now: &now}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Domains(context.Background()); err != nil {
+			t.Fatalf("Domains() error = %v", err)
+		}
+	}
+	if logins != 1 || !auth.Authenticated() {
+		t.Errorf("Domains() logged in %d times, want 1 session reused", logins)
+	}
+
+	if err := auth.KeepAlive(ctx, c); !errors.Is(err, context.Canceled) {
+		t.Errorf("KeepAlive() error = %v, want context.Canceled", err)
+	}
+	// Renewing at 15s and 30s takes at least 30s; a renewal margin beyond the lifetime would renew without waiting.
+	if elapsed := now.Sub(start); elapsed < 30*time.Second {
+		t.Errorf("KeepAlive() renewed twice after %v, want at least 30s", elapsed)
+	}
+}
+
+func TestClient_ChangeRate(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"response":{"serial":2,"changed":[]}}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC)
+	c := &Client{BaseURL: server.URL, Clock: steppedClock{now: &now}, ChangeRate: &ChangeRatePolicy{Max: 2, Window: time.Minute}}
+	record := Record{Name: "home", Type: "A", Content: "192.0.2.1"}
+
+	if _, err := c.UpdateRecord(context.Background(), "example.com", 1, record); err != nil {
+		t.Fatalf("UpdateRecord() error = %v", err)
+	}
+	if _, err := c.ModifyRecords(context.Background(), "example.com", ModifyRecord(1, record), CreateRecord(record)); err != nil {
+		t.Fatalf("ModifyRecords() error = %v", err)
+	}
+	if _, err := c.UpdateRecord(context.Background(), "Example.com.", 1, record); !errors.Is(err, ErrChangeRateExceeded) {
+		t.Errorf("UpdateRecord() error = %v, want ErrChangeRateExceeded", err)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+
+	if _, err := c.UpdateRecord(context.Background(), "example.com", 2, record); err != nil {
+		t.Errorf("UpdateRecord() of another record error = %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := c.UpdateRecord(context.Background(), "example.com", 1, record); err != nil {
+		t.Errorf("UpdateRecord() after window error = %v", err)
+	}
+
+	events, cancel := c.Subscribe(8)
+	defer cancel()
+	c.ChangeRate.AlertOnly = true
+	for i := 0; i < 2; i++ {
+		if _, err := c.UpdateRecord(context.Background(), "example.com", 1, record); err != nil {
+			t.Errorf("UpdateRecord() with AlertOnly error = %v", err)
+		}
+	}
+
+	for e := range events {
+		if e, ok := e.(*ChangeRateExceededEvent); ok {
+			if e.Record != "#1" || e.Count != 3 || !e.Allowed {
+				t.Errorf("ChangeRateExceededEvent = %+v, want #1 allowed at count 3", e)
+			}
+			break
+		}
+	}
+}
+
+func TestClient_Register(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":12,"email":"new@example.com","realname":"New User"}}`, &req)
+	c.Authenticator = &ApiKeyAuthenticator{User: "admin@example.com", Key: "secret"}
+
+	res, err := c.Register(context.Background(), "new@example.com", "New User", "hunter2")
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/register" {
+		t.Errorf("request = %s %s, want POST /1.0/register", req.Method, req.URL.Path)
+	}
+	if req.Header.Get("X-API-User") != "" {
+		t.Errorf("Register() sent the client's credentials")
+	}
+	if res.Id != 12 || res.Email != "new@example.com" {
+		t.Errorf("Register() = %+v, want account 12", res)
+	}
+}
+
+func TestClient_ConfirmRegistration(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"success":"Registration confirmed."}}`, &req)
+	c.Authenticator = &ApiKeyAuthenticator{User: "admin@example.com", Key: "secret"}
+
+	if err := c.ConfirmRegistration(context.Background(), 12, "abc123"); err != nil {
+		t.Fatalf("ConfirmRegistration() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/register/confirm/12" {
+		t.Errorf("request = %s %s, want POST /1.0/register/confirm/12", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"code":"abc123"}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if req.Header.Get("X-API-User") != "" {
+		t.Errorf("ConfirmRegistration() sent the client's credentials")
+	}
+}
+
+func TestClient_RequestPasswordReset(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"success":"Password reset was submitted, please check your email for further instructions."}}`, &req)
+	c.Authenticator = &ApiKeyAuthenticator{User: "admin@example.com", Key: "secret"}
+
+	if err := c.RequestPasswordReset(context.Background(), "user@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/forgotpassword" {
+		t.Errorf("request = %s %s, want POST /1.0/forgotpassword", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"email":"user@example.com"}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if req.Header.Get("X-API-User") != "" {
+		t.Errorf("RequestPasswordReset() sent the client's credentials")
+	}
+}
+
+func TestClient_ResetPassword(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{}}`, &req)
+
+	if err := c.ResetPassword(context.Background(), 12, "abc123", "hunter3"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/forgotpassword/confirm/12" {
+		t.Errorf("request = %s %s, want POST /1.0/forgotpassword/confirm/12", req.Method, req.URL.Path)
+	}
+}
+
+func TestClient_RedirectReplaysBody(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/moved" {
+			http.Redirect(w, r, "/moved", http.StatusTemporaryRedirect)
+			return
+		}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		_, _ = w.Write([]byte(`{"response":{"serial":2,"changed":[]}}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	if _, err := c.ModifyRecords(context.Background(), "example.com", DeleteRecord(1)); err != nil {
+		t.Fatalf("ModifyRecords() error = %v", err)
+	}
+
+	if want := `{"data":{"records":[{"id":1,"delete":true}]}}`; strings.TrimSpace(body) != want {
+		t.Errorf("redirected request body = %q, want %q", body, want)
+	}
+}
+
+// recordingCodec is a Codec that uses encoding/json, and records the types of the values it decodes.
+type recordingCodec struct {
+	StandardCodec
+	decoded []reflect.Type
+}
+
+func (c *recordingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.decoded = append(c.decoded, reflect.TypeOf(v))
+	return c.StandardCodec.Unmarshal(data, v)
+}
+
+func TestClient_CodecDecodesResponses(t *testing.T) {
+	c := testServer(t, `{"response":{"records":[{"id":"1","name":"www","type":"A","content":"192.0.2.1","ttl":"300"}],"soa":{"serial":"2"}}}`, nil)
+	codec := &recordingCodec{}
+	c.Codec = codec
+
+	res, err := c.Records(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Records() error = %v", err)
+	}
+	if len(res.Records) != 1 || res.Records[0].TTL != 300 || res.Soa.Serial != 2 {
+		t.Errorf("Records() = %+v, want one record at serial 2", res)
+	}
+
+	// Response types that decode themselves with encoding/json would bypass the codec.
+	unmarshaler := reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	for _, decoded := range codec.decoded {
+		if decoded.Implements(unmarshaler) {
+			t.Errorf("codec was given %s, which decodes itself", decoded)
+		}
+	}
+}
+
+func TestClient_DNSSECKeysUnparseable(t *testing.T) {
+	c := testServer(t, `{"response":{"domain":"example.com","dnssec":"true","DNSSEC":{
+		"ds":["example.com. IN DS 2371 13 2 1F987CC6583E92DF0890718C42", "example.com. IN DS unexpected"],
+		"dnskey":["example.com. IN DNSKEY 257 3"]
+	}}}`, nil)
+
+	info, err := c.DNSSECKeys(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("DNSSECKeys() error = %v", err)
+	}
+
+	if len(info.DS) != 2 || info.DS[0].KeyTag != 2371 || info.DS[0].Digest != "1F987CC6583E92DF0890718C42" {
+		t.Fatalf("DS = %+v, want the parseable record decoded", info.DS)
+	}
+	if want := (DSRecord{Raw: "example.com. IN DS unexpected"}); info.DS[1] != want {
+		t.Errorf("DS[1] = %+v, want %+v", info.DS[1], want)
+	}
+	if want := (DNSKEYRecord{Raw: "example.com. IN DNSKEY 257 3"}); len(info.DNSKEY) != 1 || info.DNSKEY[0] != want {
+		t.Errorf("DNSKEY = %+v, want only Raw populated", info.DNSKEY)
+	}
+}
+
+func TestClient_EnableDNSSEC(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"7","domain":"example.com","disabled":"false","dnssec":"true"}}`, &req)
+
+	res, err := c.EnableDNSSEC(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("EnableDNSSEC() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"dnssec":true}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if res.Id != 7 || !res.DNSSECEnabled {
+		t.Errorf("EnableDNSSEC() = %+v, want domain 7 signed", res)
+	}
+}
+
+func TestClient_DisableDNSSEC(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"7","domain":"example.com","disabled":"false","dnssec":"false"}}`, &req)
+
+	res, err := c.DisableDNSSEC(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("DisableDNSSEC() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com", req.Method, req.URL.Path)
+	}
+	// An explicit false is sent, rather than being omitted.
+	if body, want := requestBody(t, req), `{"data":{"dnssec":false}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if res.Id != 7 || res.DNSSECEnabled {
+		t.Errorf("DisableDNSSEC() = %+v, want domain 7 unsigned", res)
+	}
+}
+
+func TestClient_EnableDNSSEC_Frozen(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"7","domain":"example.com","dnssec":"true"}}`, &req)
+	c.Freeze = &FreezePolicy{Domains: []string{"example.com"}}
+
+	if _, err := c.EnableDNSSEC(context.Background(), "example.com"); !errors.Is(err, ErrFrozen) {
+		t.Errorf("EnableDNSSEC() error = %v, want ErrFrozen", err)
+	}
+	if _, err := c.DisableDNSSEC(context.Background(), "example.com"); !errors.Is(err, ErrFrozen) {
+		t.Errorf("DisableDNSSEC() error = %v, want ErrFrozen", err)
+	}
+	if req != nil {
+		t.Errorf("frozen domain was sent a request: %s %s", req.Method, req.URL)
+	}
+}
+
+func TestClient_ImportZone(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"serial":"2020091302"}}`, &req)
+
+	res, err := c.ImportZone(context.Background(), "example.com", "www IN A 192.0.2.1\n", ImportZoneOpts{})
+	if err != nil {
+		t.Fatalf("ImportZone() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com/import" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com/import", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"zone":"www IN A 192.0.2.1\n"}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if res.Serial != 2020091302 {
+		t.Errorf("ImportZone() serial = %d, want 2020091302", res.Serial)
+	}
+}
+
+func TestClient_UpdateSOA(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, strings.TrimSpace(string(body))))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"response":{"records":[],"soa":{"primaryNS":"ns1.mydnshost.co.uk.","adminAddress":"dnsadmin.dataforce.org.uk.","serial":"2020091301","refresh":"86400","retry":"7200","expire":"2419200","min_ttl":"60"}}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"response":{"serial":"2020091302"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	res, err := c.UpdateSOA(context.Background(), "example.com", SOA{AdminAddress: "hostmaster.example.com."})
+	if err != nil {
+		t.Fatalf("UpdateSOA() error = %v", err)
+	}
+
+	want := []string{
+		"GET /1.0/domains/example.com/records ",
+		`POST /1.0/domains/example.com/records {"data":{"soa":{"primaryNS":"ns1.mydnshost.co.uk.","adminAddress":"hostmaster.example.com.","refresh":86400,"retry":7200,"expire":2419200,"min_ttl":60}}}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+	if res.Serial != 2020091302 {
+		t.Errorf("UpdateSOA() serial = %d, want 2020091302", res.Serial)
+	}
+}
+
+func TestClient_UpdateSOA_Serial(t *testing.T) {
+	soa := SOA{PrimaryNS: "ns1.example.com.", AdminAddress: "hostmaster.example.com.", Refresh: 86400, Retry: 7200, Expire: 2419200, MinTTL: 60}
+
+	tests := []struct {
+		name    string
+		serial  uint64
+		wantErr bool
+	}{
+		{"matching", 2020091301, false},
+		{"stale", 2020091300, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req *http.Request
+			// The same response serves both the records lookup and the change.
+			c := testServer(t, `{"response":{"records":[],"soa":{"serial":"2020091301"},"serial":"2020091302"}}`, &req)
+
+			soa.Serial = tt.serial
+			_, err := c.UpdateSOA(context.Background(), "example.com", soa)
+			if tt.wantErr {
+				if !errors.Is(err, ErrConcurrentModification) {
+					t.Fatalf("UpdateSOA() error = %v, want ErrConcurrentModification", err)
+				}
+				if req.Method != http.MethodGet {
+					t.Errorf("UpdateSOA() sent %s %s after the serial didn't match", req.Method, req.URL.Path)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateSOA() error = %v", err)
+			}
+			want := `{"data":{"soa":{"primaryNS":"ns1.example.com.","adminAddress":"hostmaster.example.com.","refresh":86400,"retry":7200,"expire":2419200,"min_ttl":60}}}`
+			if body := requestBody(t, req); req.Method != http.MethodPost || body != want {
+				t.Errorf("request = %s %s, want POST %s", req.Method, body, want)
+			}
+		})
+	}
+}
+
+func TestClient_CreateDomain(t *testing.T) {
+	var requests []string
+	domainsStatus := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, strings.TrimSpace(string(body))))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"response":{"id":"7","domain":"example.com","disabled":"false","dnssec":"false"}}`))
+		} else {
+			w.WriteHeader(domainsStatus)
+			_, _ = w.Write([]byte(`{"response":{"example.com":"owner"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	res, err := c.CreateDomain(context.Background(), "example.com", CreateDomainOpts{Owner: "3"})
+	if err != nil {
+		t.Fatalf("CreateDomain() error = %v", err)
+	}
+
+	want := []string{
+		`POST /1.0/domains {"data":{"domain":"example.com","owner":"3"}}`,
+		"GET /1.0/domains ",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+	if res.Id != 7 || res.Domain != "example.com" || res.UserAccess != LevelOwner {
+		t.Errorf("CreateDomain() = %+v, want domain 7 owned by the current user", res)
+	}
+
+	// If only the access lookup fails, the created domain is still returned.
+	domainsStatus = http.StatusBadGateway
+	res, err = c.CreateDomain(context.Background(), "example.com", CreateDomainOpts{})
+	if err == nil {
+		t.Fatalf("CreateDomain() with failing access lookup should fail")
+	}
+	if res == nil || res.Id != 7 {
+		t.Errorf("CreateDomain() with failing access lookup = %+v, want the created domain", res)
+	}
+}
+
+func TestClient_UpdateDomain(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"id":"7","domain":"example.com","disabled":"true","defaultttl":"3600","dnssec":"false"}}`, &req)
+
+	disabled := true
+	res, err := c.UpdateDomain(context.Background(), "example.com", DomainSettings{Disabled: &disabled, DefaultTTL: intPtr(3600)})
+	if err != nil {
+		t.Fatalf("UpdateDomain() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"disabled":true,"defaultttl":3600}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if !res.Disabled || res.DefaultTTL != 3600 {
+		t.Errorf("UpdateDomain() = %+v, want disabled with TTL 3600", res)
+	}
+}
+
+func TestClient_DeleteDomain(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"deleted":true}}`, &req)
+
+	res, err := c.DeleteDomain(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("DeleteDomain() error = %v", err)
+	}
+
+	if req.Method != http.MethodDelete || req.URL.Path != "/1.0/domains/example.com" {
+		t.Errorf("request = %s %s, want DELETE /1.0/domains/example.com", req.Method, req.URL.Path)
+	}
+	if !res.Deleted {
+		t.Errorf("DeleteDomain() = %+v, want deleted", res)
+	}
+}
+
+func TestClient_DeleteDomain_Lenient(t *testing.T) {
+	for _, deleted := range []string{`"true"`, `"1"`, `1`} {
+		t.Run(deleted, func(t *testing.T) {
+			var req *http.Request
+			c := testServer(t, `{"response":{"deleted":`+deleted+`}}`, &req)
+
+			res, err := c.DeleteDomain(context.Background(), "example.com")
+			if err != nil {
+				t.Fatalf("DeleteDomain() error = %v", err)
+			}
+			if !res.Deleted {
+				t.Errorf("DeleteDomain() = %+v, want deleted", res)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteDomain_Frozen(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"deleted":true}}`, &req)
+	c.Freeze = &FreezePolicy{Domains: []string{"example.com"}}
+
+	if _, err := c.DeleteDomain(context.Background(), "Example.com."); !errors.Is(err, ErrFrozen) {
+		t.Errorf("DeleteDomain() of frozen domain error = %v, want ErrFrozen", err)
+	}
+	if req != nil {
+		t.Errorf("frozen domain was sent a request: %s %s", req.Method, req.URL)
+	}
+
+	if _, err := c.DeleteDomain(WithFreezeOverride(context.Background()), "example.com"); err != nil {
+		t.Errorf("DeleteDomain() with override error = %v", err)
+	}
+}
+
+func TestClient_DeleteDomain_Error(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"error":"Domain not found."}`, &req)
+
+	_, err := c.DeleteDomain(context.Background(), "example.com")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Domain not found." {
+		t.Errorf("DeleteDomain() error = %v, want the API's error", err)
+	}
+}
+
+func TestClient_SyncDomain(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"status":"ok"}}`, &req)
+
+	if err := c.SyncDomain(context.Background(), "example.com"); err != nil {
+		t.Fatalf("SyncDomain() error = %v", err)
+	}
+
+	if req.Method != http.MethodGet || req.URL.Path != "/1.0/domains/example.com/sync" {
+		t.Errorf("request = %s %s, want GET /1.0/domains/example.com/sync", req.Method, req.URL.Path)
+	}
+}
+
+func TestClient_Connect(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		response    string
+		wantInvalid bool
+	}{
+		{"rejected", http.StatusUnauthorized, `{"error":"Invalid API key"}`, true},
+		{"outage", http.StatusServiceUnavailable, `<html>Service Unavailable</html>`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.Path, "/1.0/ping/") {
+					_, _ = w.Write([]byte(`{"response":{"time":"123"}}`))
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			t.Cleanup(server.Close)
+			c := &Client{BaseURL: server.URL + "/1.0/", Authenticator: &ApiKeyAuthenticator{User: "admin@example.com", Key: "key"}}
+
+			_, err := c.Connect(context.Background())
+			if err == nil {
+				t.Fatalf("Connect() should fail")
+			}
+			if got := errors.Is(err, ErrInvalidCredentials); got != tt.wantInvalid {
+				t.Errorf("Connect() error = %v, want ErrInvalidCredentials %v", err, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestClient_ModifyRecordsIdempotent(t *testing.T) {
+	failures := []struct {
+		name string
+		fail func(w http.ResponseWriter)
+	}{
+		{"connection dropped", func(w http.ResponseWriter) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		}},
+		{"bad gateway", func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+		}},
+		{"gateway timeout", func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusGatewayTimeout)
+			_, _ = w.Write([]byte("<html><body>504 Gateway Time-out</body></html>"))
+		}},
+	}
+	for _, failure := range failures {
+		t.Run(failure.name, func(t *testing.T) {
+			var posts []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == http.MethodGet {
+					// The creation was applied before the request failed, but the deletion wasn't.
+					_, _ = w.Write([]byte(`{"response":{"records":[
+						{"id":"4","name":"www","type":"A","content":"192.0.2.1","ttl":"300"},
+						{"id":"5","name":"old","type":"A","content":"192.0.2.2","ttl":"300"}
+					],"soa":{"serial":"2"}}}`))
+					return
+				}
+
+				body, _ := ioutil.ReadAll(r.Body)
+				posts = append(posts, string(body))
+				if len(posts) == 1 {
+					failure.fail(w)
+					return
+				}
+				_, _ = w.Write([]byte(`{"response":{"serial":"3","changed":[{"id":"5","deleted":"true"}]}}`))
+			}))
+			t.Cleanup(server.Close)
+			c := &Client{BaseURL: server.URL + "/1.0/"}
+
+			res, err := c.ModifyRecordsIdempotent(context.Background(), "example.com",
+				CreateRecord(Record{Name: "www", Type: "A", Content: "192.0.2.1", TTL: 300}), DeleteRecord(5))
+			if err != nil {
+				t.Fatalf("ModifyRecordsIdempotent() error = %v", err)
+			}
+
+			want := []string{
+				`{"data":{"records":[{"name":"www","type":"A","content":"192.0.2.1","ttl":300},{"id":5,"delete":true}]}}`,
+				`{"data":{"records":[{"id":5,"delete":true}]}}`,
+			}
+			if !reflect.DeepEqual(posts, want) {
+				t.Errorf("requests = %q, want %q", posts, want)
+			}
+			if res.Serial != 3 {
+				t.Errorf("ModifyRecordsIdempotent() serial = %d, want 3", res.Serial)
+			}
+		})
+	}
+}
+
+func TestClient_ModifyRecordsIdempotent_DefiniteFailure(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("<html><body>503 Service Unavailable</body></html>"))
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	_, err := c.ModifyRecordsIdempotent(context.Background(), "example.com", DeleteRecord(5))
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("ModifyRecordsIdempotent() error = %v, want a 503 *ResponseError", err)
+	}
+	if want := []string{"POST /1.0/domains/example.com/records"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestClient_ModifyRecordsAtSerial_Mismatch(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"records":[],"soa":{"serial":"2020091302"}}}`, &req)
+
+	_, err := c.ModifyRecordsAtSerial(context.Background(), "example.com", 2020091301, DeleteRecord(1))
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("ModifyRecordsAtSerial() error = %v, want ErrConcurrentModification", err)
+	}
+	if req.Method != http.MethodGet {
+		t.Errorf("ModifyRecordsAtSerial() sent %s %s after the serial didn't match", req.Method, req.URL.Path)
+	}
+}
+
+func TestClient_ModifyRecordsAtSerial_SerialArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected uint64
+		current  string
+		want     string
+	}{
+		{"newer after wrapping", 4294967295, "3", "expected serial 4294967295 but found newer serial 3"},
+		{"older", 2020091302, "2020091301", "expected serial 2020091302 but found 2020091301"},
+		{"undefined", 1, "2147483649", "expected serial 1 but found 2147483649"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req *http.Request
+			c := testServer(t, `{"response":{"records":[],"soa":{"serial":"`+tt.current+`"}}}`, &req)
+
+			_, err := c.ModifyRecordsAtSerial(context.Background(), "example.com", tt.expected, DeleteRecord(1))
+			if !errors.Is(err, ErrConcurrentModification) {
+				t.Fatalf("ModifyRecordsAtSerial() error = %v, want ErrConcurrentModification", err)
+			}
+			if !strings.HasSuffix(err.Error(), tt.want) {
+				t.Errorf("ModifyRecordsAtSerial() error = %q, want it to end %q", err, tt.want)
+			}
+			if req.Method != http.MethodGet {
+				t.Errorf("ModifyRecordsAtSerial() sent %s %s after the serial didn't match", req.Method, req.URL.Path)
+			}
+		})
+	}
+}
+
+func TestClient_ModifyRecordsAtSerial(t *testing.T) {
+	var req *http.Request
+	// The same response serves both the records lookup and the change.
+	c := testServer(t, `{"response":{"records":[],"soa":{"serial":"2020091301"},"serial":"2020091302","changed":[{"id":"1","deleted":"true"}]}}`, &req)
+
+	res, err := c.ModifyRecordsAtSerial(context.Background(), "example.com", 2020091301, DeleteRecord(1))
+	if err != nil {
+		t.Fatalf("ModifyRecordsAtSerial() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com/records" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com/records", req.Method, req.URL.Path)
+	}
+	if body, want := requestBody(t, req), `{"data":{"records":[{"id":1,"delete":true}]}}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+	if res.Serial != 2020091302 || len(res.Changed) != 1 || !res.Changed[0].Deleted {
+		t.Errorf("ModifyRecordsAtSerial() = %+v, want record 1 deleted at serial 2020091302", res)
+	}
+}
+
+func TestClient_ImportZoneMerge(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, strings.TrimSpace(string(body))))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"response":{"records":[
+				{"id":"1","name":"","type":"NS","content":"ns1.mydnshost.co.uk","ttl":"86400"},
+				{"id":"2","name":"www","type":"A","content":"192.0.2.1","ttl":"300"},
+				{"id":"3","name":"old","type":"A","content":"192.0.2.9","ttl":"300"}
+			],"soa":{"serial":"1"}}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"response":{"serial":"2","changed":[]}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	zone := "@ 3600 IN NS ns1.mydnshost.co.uk.\nwww 300 IN A 192.0.2.1\nwww 600 IN A 192.0.2.2\n"
+	res, err := c.ImportZone(context.Background(), "example.com", zone, ImportZoneOpts{Merge: true})
+	if err != nil {
+		t.Fatalf("ImportZone() error = %v", err)
+	}
+
+	// The existing records are kept, with the NS TTL taken from the zone file, and no import request is made.
+	want := []string{
+		"GET /1.0/domains/example.com/records ",
+		`POST /1.0/domains/example.com/records {"data":{"records":[{"ttl":3600,"id":1},{"name":"www","type":"A","content":"192.0.2.2","ttl":600}]}}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+	if res.Serial != 2 {
+		t.Errorf("ImportZone() serial = %d, want 2", res.Serial)
+	}
+}
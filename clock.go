@@ -0,0 +1,68 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Clock provides the current time and timers to a Client. Supplying a fake implementation allows time-dependent
+// behaviour such as retries and polling to be tested deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Random provides random numbers used to add jitter to delays.
+type Random interface {
+	// Int63n returns a non-negative pseudo-random number in [0,n).
+	Int63n(n int64) int64
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type globalRandom struct{}
+
+func (globalRandom) Int63n(n int64) int64 {
+	return rand.Int63n(n)
+}
+
+func (c *Client) clock() Clock {
+	if c.Clock == nil {
+		return systemClock{}
+	}
+	return c.Clock
+}
+
+func (c *Client) now() time.Time {
+	return c.clock().Now()
+}
+
+func (c *Client) random() Random {
+	if c.Rand == nil {
+		return globalRandom{}
+	}
+	return c.Rand
+}
+
+// sleep waits for the given duration according to the client's clock, returning false if ctx is done first.
+func (c *Client) sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-c.clock().After(d):
+		return true
+	}
+}
@@ -0,0 +1,31 @@
+package mydnshost_go_api
+
+import "encoding/json"
+
+// Codec encodes and decodes the JSON bodies of API requests and responses. It allows an alternative JSON
+// implementation to be used in place of encoding/json. Implementations must support json.RawMessage, honour
+// the standard `json` struct tags, and call the UnmarshalJSON methods of types implementing json.Unmarshaler, which
+// are used to decode scalar fields leniently. As with encoding/json, Unmarshal must not retain the data passed to it after
+// returning, as the underlying buffer is reused.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StandardCodec is a Codec that uses encoding/json. It is used if a Client does not specify a Codec.
+type StandardCodec struct{}
+
+func (StandardCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StandardCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (c *Client) codec() Codec {
+	if c.Codec == nil {
+		return StandardCodec{}
+	}
+	return c.Codec
+}
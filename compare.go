@@ -0,0 +1,177 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// RecordChange pairs a record with its counterpart in another copy of the same zone, where the two have the same
+// name, type, content and priority but differ in their other fields.
+type RecordChange struct {
+	Source ExistingRecord
+	Target ExistingRecord
+}
+
+// ZoneDiff describes the differences between two copies of a domain's records, such as the same domain in a
+// production and a staging account.
+type ZoneDiff struct {
+	Domain string
+	// Missing lists records in the source zone that aren't in the target.
+	Missing []ExistingRecord
+	// Extra lists records in the target zone that aren't in the source.
+	Extra []ExistingRecord
+	// Changed lists records in both zones whose TTL or disabled state differs.
+	Changed []RecordChange
+}
+
+// Empty reports whether the two zones have the same records.
+func (d *ZoneDiff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// Operations returns the operations that would make the target zone match the source.
+func (d *ZoneDiff) Operations() []RecordOperation {
+	var operations []RecordOperation
+	for i := range d.Extra {
+		operations = append(operations, DeleteRecord(d.Extra[i].Id))
+	}
+	for i := range d.Changed {
+		operations = append(operations, syncRecord(d.Changed[i].Target.Id, d.Changed[i].Source.Record))
+	}
+	for i := range d.Missing {
+		operations = append(operations, CreateRecord(d.Missing[i].Record))
+	}
+	return operations
+}
+
+// syncRecord changes the record with the given ID to match the given record. Unlike ModifyRecord, the TTL and
+// disabled state are always sent, as they are what differs between changed records: a zero TTL or nil Disabled
+// would otherwise be omitted and leave the target unchanged.
+func syncRecord(id int, record Record) RecordOperation {
+	res, _ := json.Marshal(struct {
+		Record
+		TTL      int  `json:"ttl"`
+		Disabled bool `json:"disabled"`
+		Id       int  `json:"id"`
+	}{
+		Record:   record,
+		TTL:      record.TTL,
+		Disabled: record.Disabled != nil && *record.Disabled,
+		Id:       id,
+	})
+	return res
+}
+
+// CompareZones retrieves a domain's records from two providers, such as Clients configured for different accounts
+// or API instances, and returns the differences between them. Every record is compared, including NS records.
+func CompareZones(ctx context.Context, source, target ZoneProvider, domain string) (*ZoneDiff, error) {
+	sourceRecords, err := source.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	targetRecords, err := target.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffRecords(domain, sourceRecords.Records, targetRecords.Records), nil
+}
+
+// ReplicateZone changes the target's copy of a domain to match the source's, returning the differences that
+// were replicated. No request is made to change the target if the zones already match. If the target is a
+// *Client, its Guardrails stop an empty or incomplete source from wiping out the target.
+func ReplicateZone(ctx context.Context, source, target ZoneProvider, domain string) (*ZoneDiff, error) {
+	diff, err := CompareZones(ctx, source, target, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if diff.Empty() {
+		return diff, nil
+	}
+
+	if _, err := target.ModifyRecords(ctx, domain, diff.Operations()...); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+func diffRecords(domain string, source, target []ExistingRecord) *ZoneDiff {
+	diff := &ZoneDiff{Domain: domain}
+	used := make([]bool, len(target))
+	index := indexRecords(target)
+
+	// Pair up identical records first, so that a record that only differs in TTL isn't paired with a target
+	// record that has an identical counterpart elsewhere in the source.
+	var unmatched []ExistingRecord
+	for i := range source {
+		if j := index.find(source[i].Record, target, used, identicalRecord); j >= 0 {
+			used[j] = true
+		} else {
+			unmatched = append(unmatched, source[i])
+		}
+	}
+
+	for i := range unmatched {
+		if j := index.find(unmatched[i].Record, target, used, equivalentRecord); j >= 0 {
+			used[j] = true
+			diff.Changed = append(diff.Changed, RecordChange{Source: unmatched[i], Target: target[j]})
+		} else {
+			diff.Missing = append(diff.Missing, unmatched[i])
+		}
+	}
+
+	for j := range target {
+		if !used[j] {
+			diff.Extra = append(diff.Extra, target[j])
+		}
+	}
+	return diff
+}
+
+// recordIndex holds the positions of records, grouped by recordKey. Records can only be equivalent if they have the
+// same key, so matches can be found without comparing against every record in a zone.
+type recordIndex map[string][]int
+
+func indexRecords(records []ExistingRecord) recordIndex {
+	index := make(recordIndex, len(records))
+	for i := range records {
+		key := recordKey(records[i].Record)
+		index[key] = append(index[key], i)
+	}
+	return index
+}
+
+// find returns the position of the first unused record that matches the given record, or -1 if there is none.
+func (index recordIndex) find(record Record, records []ExistingRecord, used []bool, match func(a, b Record) bool) int {
+	for _, i := range index[recordKey(record)] {
+		if !used[i] && match(record, records[i].Record) {
+			return i
+		}
+	}
+	return -1
+}
+
+// recordKey identifies the fields compared by equivalentRecord.
+func recordKey(r Record) string {
+	priority := "-"
+	if r.Priority != nil {
+		priority = strconv.Itoa(*r.Priority)
+	}
+	return strings.ToLower(r.Name) + "\x00" + strings.ToLower(r.Type) + "\x00" + r.Content + "\x00" + priority
+}
+
+// equivalentRecord checks if two records have the same name, type, content and priority.
+func equivalentRecord(a, b Record) bool {
+	return sameRecord(a, b) && sameRecord(b, a)
+}
+
+// identicalRecord checks if two records are equivalent and also have the same TTL and disabled state.
+func identicalRecord(a, b Record) bool {
+	disabledA := a.Disabled != nil && *a.Disabled
+	disabledB := b.Disabled != nil && *b.Disabled
+	return equivalentRecord(a, b) && a.TTL == b.TTL && disabledA == disabledB
+}
@@ -0,0 +1,230 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDiffRecords(t *testing.T) {
+	source := []ExistingRecord{
+		{Id: 1, Record: Record{Name: "", Type: "A", Content: "192.0.2.1", TTL: 3600}},
+		{Id: 2, Record: Record{Name: "www", Type: "CNAME", Content: "example.com", TTL: 300}},
+		{Id: 3, Record: Record{Name: "", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: intPtr(10)}},
+	}
+	target := []ExistingRecord{
+		{Id: 10, Record: Record{Name: "www", Type: "CNAME", Content: "example.com", TTL: 3600}},
+		{Id: 11, Record: Record{Name: "", Type: "A", Content: "192.0.2.1", TTL: 3600, Disabled: boolPtr(false)}},
+		{Id: 12, Record: Record{Name: "", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: intPtr(20)}},
+	}
+
+	diff := diffRecords("example.com", source, target)
+
+	if len(diff.Changed) != 1 || diff.Changed[0].Source.Id != 2 || diff.Changed[0].Target.Id != 10 {
+		t.Errorf("Changed = %+v, want record 2 paired with 10", diff.Changed)
+	}
+	if len(diff.Missing) != 1 || diff.Missing[0].Id != 3 {
+		t.Errorf("Missing = %+v, want record 3", diff.Missing)
+	}
+	if len(diff.Extra) != 1 || diff.Extra[0].Id != 12 {
+		t.Errorf("Extra = %+v, want record 12", diff.Extra)
+	}
+
+	want := []string{
+		`{"id":12,"delete":true}`,
+		`{"name":"www","type":"CNAME","content":"example.com","ttl":300,"disabled":false,"id":10}`,
+		`{"type":"MX","content":"mail.example.com","ttl":3600,"priority":10}`,
+	}
+	operations := diff.Operations()
+	if len(operations) != len(want) {
+		t.Fatalf("Operations() = %d operations, want %d", len(operations), len(want))
+	}
+	for i := range want {
+		if string(operations[i]) != want[i] {
+			t.Errorf("Operations()[%d] = %s, want %s", i, operations[i], want[i])
+		}
+	}
+
+	if !diffRecords("example.com", source, source).Empty() {
+		t.Errorf("diff of identical zones is not empty")
+	}
+}
+
+func TestDiffRecords_Matching(t *testing.T) {
+	source := []ExistingRecord{
+		{Id: 1, Record: Record{Name: "WWW", Type: "cname", Content: "example.com", TTL: 300}},
+		{Id: 2, Record: Record{Name: "", Type: "TXT", Content: "v=spf1 -all", TTL: 300}},
+		{Id: 3, Record: Record{Name: "", Type: "TXT", Content: "v=spf1 -all", TTL: 300}},
+		{Id: 4, Record: Record{Name: "", Type: "MX", Content: "mail.example.com", TTL: 300}},
+		{Id: 5, Record: Record{Name: "", Type: "TXT", Content: "V=SPF1 -ALL", TTL: 300}},
+	}
+	target := []ExistingRecord{
+		{Id: 10, Record: Record{Name: "", Type: "TXT", Content: "v=spf1 -all", TTL: 600}},
+		{Id: 11, Record: Record{Name: "www", Type: "CNAME", Content: "example.com", TTL: 300}},
+		{Id: 12, Record: Record{Name: "", Type: "TXT", Content: "v=spf1 -all", TTL: 300}},
+		{Id: 13, Record: Record{Name: "", Type: "MX", Content: "mail.example.com", TTL: 300, Priority: intPtr(10)}},
+	}
+
+	diff := diffRecords("example.com", source, target)
+
+	// Names and types match regardless of case, but content and priority must be the same. Duplicates are paired
+	// with an identical record before one that only differs in TTL.
+	if len(diff.Changed) != 1 || diff.Changed[0].Source.Id != 3 || diff.Changed[0].Target.Id != 10 {
+		t.Errorf("Changed = %+v, want record 3 paired with 10", diff.Changed)
+	}
+	if len(diff.Missing) != 2 || diff.Missing[0].Id != 4 || diff.Missing[1].Id != 5 {
+		t.Errorf("Missing = %+v, want records 4 and 5", diff.Missing)
+	}
+	if len(diff.Extra) != 1 || diff.Extra[0].Id != 13 {
+		t.Errorf("Extra = %+v, want record 13", diff.Extra)
+	}
+}
+
+func TestZoneDiff_WriteMarkdown(t *testing.T) {
+	diff := &ZoneDiff{
+		Domain:  "example.com",
+		Missing: []ExistingRecord{{Record: Record{Name: "", Type: "TXT", Content: "a|b", TTL: 300}}},
+		Extra:   []ExistingRecord{{Record: Record{Name: "", Type: "NS", Content: "ns1.example.net.", TTL: 86400}}},
+	}
+
+	var b strings.Builder
+	if err := diff.WriteMarkdown(&b); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"# Changes to example.com\n",
+		"| 1 | 0 | 1 |\n",
+		"- 1 NS record(s) at the zone apex will be removed",
+		`| Add | @ | TXT | a\|b | 300 |`,
+		"| Remove | @ | NS | ns1.example.net. | 86400 |",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteMarkdown() = %q, want it to contain %q", b.String(), want)
+		}
+	}
+}
+
+func TestZoneDiff_WriteHTML(t *testing.T) {
+	diff := &ZoneDiff{
+		Domain:  "example.com",
+		Missing: []ExistingRecord{{Record: Record{Name: "www", Type: "TXT", Content: "<script>", TTL: 300}}},
+	}
+
+	var b strings.Builder
+	if err := diff.WriteHTML(&b); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+
+	if !strings.Contains(b.String(), "<td>&lt;script&gt;</td>") {
+		t.Errorf("WriteHTML() = %q, want escaped record content", b.String())
+	}
+}
+
+// staticProvider is a ZoneProvider that serves a fixed set of records and records the operations it is given.
+type staticProvider struct {
+	records    []ExistingRecord
+	operations []RecordOperation
+}
+
+func (p *staticProvider) Records(ctx context.Context, domain string) (*RecordsResponse, error) {
+	return &RecordsResponse{Records: p.records}, nil
+}
+
+func (p *staticProvider) ModifyRecords(ctx context.Context, domain string, operations ...RecordOperation) (*ModifyRecordsResponse, error) {
+	p.operations = append(p.operations, operations...)
+	return &ModifyRecordsResponse{}, nil
+}
+
+func TestCompareZones(t *testing.T) {
+	source := &staticProvider{records: []ExistingRecord{
+		{Id: 1, Record: Record{Name: "www", Type: "A", Content: "192.0.2.1", TTL: 0}},
+		{Id: 2, Record: Record{Name: "api", Type: "A", Content: "192.0.2.2", TTL: 300}},
+		{Id: 3, Record: Record{Name: "new", Type: "A", Content: "192.0.2.3", TTL: 300}},
+	}}
+	target := &staticProvider{records: []ExistingRecord{
+		{Id: 10, Record: Record{Name: "www", Type: "A", Content: "192.0.2.1", TTL: 3600}},
+		{Id: 11, Record: Record{Name: "api", Type: "A", Content: "192.0.2.2", TTL: 300, Disabled: boolPtr(true)}},
+		{Id: 12, Record: Record{Name: "old", Type: "A", Content: "192.0.2.4", TTL: 300}},
+	}}
+
+	diff, err := CompareZones(context.Background(), source, target, "example.com")
+	if err != nil {
+		t.Fatalf("CompareZones() error = %v", err)
+	}
+	if len(diff.Changed) != 2 || len(diff.Missing) != 1 || len(diff.Extra) != 1 || diff.Domain != "example.com" {
+		t.Fatalf("CompareZones() = %+v, want 2 changed, 1 missing and 1 extra record", diff)
+	}
+	if len(source.operations) != 0 || len(target.operations) != 0 {
+		t.Errorf("CompareZones() changed a zone")
+	}
+
+	// The zero TTL and nil Disabled of the source are sent, so that the target is changed to match.
+	want := []string{
+		`{"id":12,"delete":true}`,
+		`{"name":"www","type":"A","content":"192.0.2.1","ttl":0,"disabled":false,"id":10}`,
+		`{"name":"api","type":"A","content":"192.0.2.2","ttl":300,"disabled":false,"id":11}`,
+		`{"name":"new","type":"A","content":"192.0.2.3","ttl":300}`,
+	}
+	operations := diff.Operations()
+	if len(operations) != len(want) {
+		t.Fatalf("Operations() = %q, want %q", operations, want)
+	}
+	for i := range want {
+		if string(operations[i]) != want[i] {
+			t.Errorf("Operations()[%d] = %s, want %s", i, operations[i], want[i])
+		}
+	}
+}
+
+func TestCompareZones_Error(t *testing.T) {
+	var req *http.Request
+	target := testServer(t, `{"error":"Unknown domain: example.com"}`, &req)
+
+	if _, err := CompareZones(context.Background(), &staticProvider{}, target, "example.com"); err == nil {
+		t.Errorf("CompareZones() succeeded, want the target's error")
+	}
+}
+
+func TestReplicateZone(t *testing.T) {
+	source := &staticProvider{records: []ExistingRecord{{Id: 1, Record: Record{Name: "www", Type: "A", Content: "192.0.2.1", TTL: 300}}}}
+	target := &staticProvider{}
+
+	diff, err := ReplicateZone(context.Background(), source, target, "example.com")
+	if err != nil {
+		t.Fatalf("ReplicateZone() error = %v", err)
+	}
+
+	if len(diff.Missing) != 1 || len(target.operations) != 1 {
+		t.Errorf("ReplicateZone() = %+v with %d operations, want 1 missing record created", diff, len(target.operations))
+	}
+	if len(source.operations) != 0 {
+		t.Errorf("ReplicateZone() changed the source")
+	}
+}
+
+func TestReplicateZone_Guardrails(t *testing.T) {
+	source := &staticProvider{}
+	var posts [][]decodedOperation
+	target := recordServer(t, map[int]ExistingRecord{
+		1: {Id: 1, Record: Record{Name: "", Type: "NS", Content: "ns1.mydnshost.co.uk"}},
+		2: {Id: 2, Record: Record{Name: "www", Type: "A", Content: "192.0.2.1"}},
+	}, &posts)
+	target.Guardrails = &Guardrails{MaxDeletePercent: 50}
+
+	if _, err := ReplicateZone(context.Background(), source, target, "example.com"); !errors.Is(err, ErrGuardrailExceeded) {
+		t.Fatalf("ReplicateZone() from an empty zone error = %v, want ErrGuardrailExceeded", err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("ReplicateZone() made %d changes despite exceeding the guardrails", len(posts))
+	}
+
+	if _, err := ReplicateZone(WithGuardrailOverride(context.Background()), source, target, "example.com"); err != nil {
+		t.Fatalf("ReplicateZone() with override error = %v", err)
+	}
+	if len(posts) != 1 || len(posts[0]) != 2 {
+		t.Errorf("ReplicateZone() with override made changes %+v, want 2 deletes", posts)
+	}
+}
@@ -0,0 +1,102 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrInvalidCredentials is returned by Connect if the API does not accept the client's credentials.
+	ErrInvalidCredentials = errors.New("credentials were not accepted")
+	// ErrNoPermission is returned by Connect if the credentials are valid but grant no access to any domains.
+	ErrNoPermission = errors.New("credentials do not grant access to any domains")
+)
+
+// Connection describes the user and domain access levels that were verified when calling Connect.
+type Connection struct {
+	User    *UserDataResponse
+	Domains DomainAccess
+}
+
+// Connect checks that the API is reachable and that the client's credentials are valid and grant access to at least
+// one domain. The user's details and domain access levels are cached and can be retrieved with Connection.
+//
+// Calling Connect is optional, but allows misconfiguration to be detected and reported clearly at startup instead
+// of causing later calls to fail in different ways. Errors wrap ErrInvalidCredentials or ErrNoPermission where the
+// cause could be identified.
+func (c *Client) Connect(ctx context.Context) (*Connection, error) {
+	if c.Authenticator == nil {
+		return nil, fmt.Errorf("%w: no authenticator configured", ErrInvalidCredentials)
+	}
+
+	if _, err := c.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("unable to reach API: %w", err)
+	}
+
+	user, err := c.UserData(ctx)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if rejectedCredentials(err) {
+		return nil, fmt.Errorf("%w: unable to retrieve user data (%s): %v", ErrInvalidCredentials, describeAuthenticator(c.Authenticator), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve user data: %w", err)
+	}
+
+	if user.User.Id == "" {
+		return nil, fmt.Errorf("%w: API did not identify a user (%s)", ErrInvalidCredentials, describeAuthenticator(c.Authenticator))
+	}
+
+	if !user.Access.DomainsRead {
+		return nil, fmt.Errorf("%w: user %s is not permitted to read domains", ErrNoPermission, user.User.Email)
+	}
+
+	domains, err := c.Domains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list domains: %w", err)
+	}
+
+	if len(domains.Readable()) == 0 {
+		return nil, fmt.Errorf("%w: user %s", ErrNoPermission, user.User.Email)
+	}
+
+	connection := &Connection{
+		User:    user,
+		Domains: domains,
+	}
+
+	c.mu.Lock()
+	c.connection = connection
+	c.mu.Unlock()
+
+	return connection, nil
+}
+
+// Connection returns the details cached by the last successful call to Connect, or nil if it has not been called.
+func (c *Client) Connection() *Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connection
+}
+
+// rejectedCredentials reports whether err shows that the API refused the client's credentials, as opposed to the
+// request failing for some other reason such as an outage.
+func rejectedCredentials(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// describeAuthenticator gives a hint as to which credentials should be checked if they aren't accepted.
+func describeAuthenticator(a ClientAuthenticator) string {
+	switch auth := a.(type) {
+	case *ApiKeyAuthenticator:
+		return fmt.Sprintf("check the API key is correct and belongs to user %q", auth.User)
+	case *DomainKeyAuthenticator:
+		return fmt.Sprintf("check the domain key is correct and belongs to domain %q", auth.Domain)
+	default:
+		return "check the configured credentials"
+	}
+}
@@ -0,0 +1,132 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &Client{Authenticator: &ApiKeyAuthenticator{User: "user@example.com", Key: "key"}}
+	calls := map[string]func() error{
+		"Connect": func() error {
+			_, err := c.Connect(ctx)
+			return err
+		},
+		"ModifyRecordsAtSerial": func() error {
+			_, err := c.ModifyRecordsAtSerial(ctx, "example.com", 1, DeleteRecord(1))
+			return err
+		},
+		"ModifyRecordsIdempotent": func() error {
+			_, err := c.ModifyRecordsIdempotent(ctx, "example.com", DeleteRecord(1))
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s() with cancelled context returned %v, want context.Canceled", name, err)
+		}
+	}
+}
+
+// cancellingTransport cancels a context once the first request made through it has completed: after its response
+// body has been closed, or as soon as it fails.
+type cancellingTransport struct {
+	cancel context.CancelFunc
+}
+
+func (t *cancellingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.cancel()
+		return nil, err
+	}
+	res.Body = &cancellingBody{ReadCloser: res.Body, cancel: t.cancel}
+	return res, nil
+}
+
+type cancellingBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancellingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func TestCancelledAfterFirstRequest(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			// Drop the connection, so that ModifyRecordsIdempotent has to check which operations were applied.
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		case strings.HasPrefix(r.URL.Path, "/1.0/ping/"):
+			_, _ = w.Write([]byte(`{"response":{"time":"123"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"response":{"records":[],"soa":{"serial":"1"}}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	for name, call := range map[string]func(ctx context.Context, c *Client) error{
+		"Connect": func(ctx context.Context, c *Client) error {
+			_, err := c.Connect(ctx)
+			return err
+		},
+		"ModifyRecordsAtSerial": func(ctx context.Context, c *Client) error {
+			_, err := c.ModifyRecordsAtSerial(ctx, "example.com", 1, DeleteRecord(1))
+			return err
+		},
+		"ModifyRecordsIdempotent": func(ctx context.Context, c *Client) error {
+			_, err := c.ModifyRecordsIdempotent(ctx, "example.com", DeleteRecord(1))
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			mu.Lock()
+			requests = nil
+			mu.Unlock()
+			c := &Client{
+				BaseURL:       server.URL + "/1.0/",
+				HTTPClient:    &http.Client{Transport: &cancellingTransport{cancel: cancel}},
+				Authenticator: &ApiKeyAuthenticator{User: "user@example.com", Key: "key"},
+			}
+
+			err := call(ctx, c)
+			if err == nil {
+				t.Fatalf("%s() cancelled after the first request succeeded", name)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(requests) != 1 {
+				t.Errorf("%s() made requests %q after being cancelled, want only the first", name, requests)
+			}
+			// ModifyRecordsIdempotent returns the original failure, as the operations were never checked.
+			if name != "ModifyRecordsIdempotent" && !errors.Is(err, context.Canceled) {
+				t.Errorf("%s() cancelled after the first request returned %v, want context.Canceled", name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Delegation describes a subdomain that is to be served by other nameservers.
+type Delegation struct {
+	// Name is the subdomain to delegate, relative to the parent domain (e.g. "dev").
+	Name string
+	// Nameservers are the fully-qualified hostnames of the child zone's nameservers.
+	Nameservers []string
+	// Glue gives the addresses of any nameservers that are themselves within the delegated subdomain, keyed by
+	// nameserver hostname. A and AAAA records are created for each.
+	Glue map[string][]string
+	// TTL is the TTL of the created records. If zero, the API's default is used.
+	TTL int
+	// SkipValidation disables checking that the nameservers are authoritative for the child zone before the
+	// delegation is created.
+	SkipValidation bool
+}
+
+// Delegate creates the NS records (and any glue records) to delegate a subdomain to other nameservers. Unless
+// SkipValidation is set, each nameserver is first queried to check it is serving the child zone with a matching NS
+// record set, and no changes are made if any are not.
+func (c *Client) Delegate(ctx context.Context, domain string, d Delegation) (*ModifyRecordsResponse, error) {
+	if d.Name == "" || len(d.Nameservers) == 0 {
+		return nil, errors.New("delegation requires a subdomain name and at least one nameserver")
+	}
+
+	if !d.SkipValidation {
+		if err := VerifyDelegation(ctx, fmt.Sprintf("%s.%s", d.Name, domain), d.Nameservers, d.Glue); err != nil {
+			return nil, err
+		}
+	}
+
+	var operations []RecordOperation
+	for _, ns := range d.Nameservers {
+		operations = append(operations, CreateRecord(Record{
+			Name:    d.Name,
+			Type:    "NS",
+			Content: normaliseHost(ns),
+			TTL:     d.TTL,
+		}))
+	}
+
+	// Glue is added in order of hostname, so that the same delegation always produces the same operations.
+	glue := make([]string, 0, len(d.Glue))
+	for ns := range d.Glue {
+		glue = append(glue, ns)
+	}
+	sort.Slice(glue, func(i, j int) bool {
+		return normaliseHost(glue[i]) < normaliseHost(glue[j])
+	})
+
+	suffix := "." + normaliseHost(domain)
+	for _, ns := range glue {
+		host := normaliseHost(ns)
+		if !strings.HasSuffix(host, suffix) {
+			return nil, fmt.Errorf("glue for %s is outside of domain %s", ns, domain)
+		}
+
+		for _, address := range d.Glue[ns] {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid glue address for %s: %s", ns, address)
+			}
+
+			recordType := "AAAA"
+			if ip.To4() != nil {
+				recordType = "A"
+			}
+
+			operations = append(operations, CreateRecord(Record{
+				Name:    strings.TrimSuffix(host, suffix),
+				Type:    recordType,
+				Content: ip.String(),
+				TTL:     d.TTL,
+			}))
+		}
+	}
+
+	return c.ModifyRecords(ctx, domain, operations...)
+}
+
+// DelegationError describes the nameservers that failed verification in VerifyDelegation.
+type DelegationError struct {
+	Zone     string
+	Failures map[string]error
+}
+
+func (e *DelegationError) Error() string {
+	servers := make([]string, 0, len(e.Failures))
+	for ns := range e.Failures {
+		servers = append(servers, ns)
+	}
+	sort.Strings(servers)
+
+	parts := make([]string, len(servers))
+	for i, ns := range servers {
+		parts[i] = fmt.Sprintf("%s: %v", ns, e.Failures[ns])
+	}
+	return fmt.Sprintf("delegation of %s is not being served correctly: %s", e.Zone, strings.Join(parts, "; "))
+}
+
+// VerifyDelegation queries each of the given nameservers directly and checks that they answer for the zone with an
+// NS record set matching the nameservers. Glue addresses, keyed by nameserver hostname, are used to contact any
+// nameservers that can't yet be resolved. If any nameserver fails, a *DelegationError is returned.
+func VerifyDelegation(ctx context.Context, zone string, nameservers []string, glue map[string][]string) error {
+	want := make([]string, len(nameservers))
+	for i := range nameservers {
+		want[i] = normaliseHost(nameservers[i])
+	}
+	sort.Strings(want)
+
+	failures := make(map[string]error)
+	for _, ns := range nameservers {
+		address := ns
+		if glueAddress := glueFor(glue, ns); glueAddress != "" {
+			address = glueAddress
+		}
+
+		got, err := lookupNS(ctx, address, zone)
+		if err != nil {
+			failures[ns] = err
+			continue
+		}
+
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			failures[ns] = fmt.Errorf("serves NS records %v, expected %v", got, want)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if len(failures) > 0 {
+		return &DelegationError{Zone: zone, Failures: failures}
+	}
+	return nil
+}
+
+// glueFor returns the first glue address given for a nameserver, if any. Hostnames are compared without regard to
+// case or a trailing dot.
+func glueFor(glue map[string][]string, ns string) string {
+	host := normaliseHost(ns)
+	for name, addresses := range glue {
+		if normaliseHost(name) == host && len(addresses) > 0 {
+			return addresses[0]
+		}
+	}
+	return ""
+}
+
+// lookupNS is used by VerifyDelegation to query nameservers. It is replaced in tests.
+var lookupNS = lookupNSAt
+
+// lookupNSAt queries the given server directly for the NS records of a zone, returning the sorted hostnames.
+func lookupNSAt(ctx context.Context, server, zone string) ([]string, error) {
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{}
+			return d.DialContext(ctx, network, net.JoinHostPort(server, "53"))
+		},
+	}
+
+	records, err := resolver.LookupNS(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, len(records))
+	for i := range records {
+		res[i] = normaliseHost(records[i].Host)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// normaliseHost lower-cases a hostname and removes any trailing dot.
+func normaliseHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
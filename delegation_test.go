@@ -0,0 +1,167 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeNameservers replaces lookupNS for the duration of a test, answering with the NS records given for each server
+// address. Servers without an entry fail as though they couldn't be reached. The addresses queried are stored in
+// queried.
+func fakeNameservers(t *testing.T, answers map[string][]string, queried *[]string) {
+	t.Helper()
+
+	lookupNS = func(ctx context.Context, server, zone string) ([]string, error) {
+		*queried = append(*queried, server+" "+zone)
+		if records, ok := answers[server]; ok {
+			return records, nil
+		}
+		return nil, fmt.Errorf("no response from %s", server)
+	}
+	t.Cleanup(func() {
+		lookupNS = lookupNSAt
+	})
+}
+
+func TestGlueFor(t *testing.T) {
+	glue := map[string][]string{
+		"NS1.dev.example.com.": {"192.0.2.53"},
+		"ns2.dev.example.com":  {"2001:db8::53", "192.0.2.54"},
+		"ns3.dev.example.com":  {},
+	}
+
+	tests := []struct {
+		ns   string
+		want string
+	}{
+		{"ns1.dev.example.com", "192.0.2.53"},
+		{"ns1.dev.example.com.", "192.0.2.53"},
+		{"NS2.DEV.EXAMPLE.COM.", "2001:db8::53"},
+		{"ns3.dev.example.com", ""},
+		{"ns.example.net", ""},
+	}
+	for _, tt := range tests {
+		if got := glueFor(glue, tt.ns); got != tt.want {
+			t.Errorf("glueFor(%q) = %q, want %q", tt.ns, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyDelegation(t *testing.T) {
+	var queried []string
+	fakeNameservers(t, map[string][]string{
+		"192.0.2.53":      {"ns.example.net", "ns1.dev.example.com"},
+		"ns.example.net":  {"ns.example.net", "ns1.dev.example.com"},
+		"ns2.example.net": {"ns.example.net"},
+	}, &queried)
+
+	glue := map[string][]string{"ns1.dev.example.com": {"192.0.2.53"}}
+	if err := VerifyDelegation(context.Background(), "dev.example.com", []string{"NS1.dev.example.com.", "ns.example.net"}, glue); err != nil {
+		t.Fatalf("VerifyDelegation() error = %v", err)
+	}
+
+	// The in-zone nameserver is contacted using its glue address.
+	if want := []string{"192.0.2.53 dev.example.com", "ns.example.net dev.example.com"}; !reflect.DeepEqual(queried, want) {
+		t.Errorf("queried = %q, want %q", queried, want)
+	}
+
+	err := VerifyDelegation(context.Background(), "dev.example.com", []string{"ns.example.net", "ns2.example.net", "ns3.example.net"}, nil)
+	var delegationErr *DelegationError
+	if !errors.As(err, &delegationErr) {
+		t.Fatalf("VerifyDelegation() error = %v, want a *DelegationError", err)
+	}
+	if len(delegationErr.Failures) != 3 {
+		t.Errorf("VerifyDelegation() failures = %v, want all three nameservers", delegationErr.Failures)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "delegation of dev.example.com is not being served correctly: ns.example.net: ") ||
+		!strings.Contains(msg, "; ns3.example.net: no response from ns3.example.net") {
+		t.Errorf("VerifyDelegation() error = %q, want failures listed in order of nameserver", msg)
+	}
+}
+
+func TestClient_Delegate(t *testing.T) {
+	var queried []string
+	fakeNameservers(t, map[string][]string{
+		"192.0.2.53":   {"ns1.dev.example.com", "ns2.dev.example.com"},
+		"192.0.2.54":   {"ns1.dev.example.com", "ns2.dev.example.com"},
+		"2001:db8::53": {"ns1.dev.example.com", "ns2.dev.example.com"},
+	}, &queried)
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"response":{"serial":"2","changed":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+	c := &Client{BaseURL: server.URL + "/1.0/"}
+
+	d := Delegation{
+		Name:        "dev",
+		Nameservers: []string{"ns2.dev.example.com.", "ns1.dev.example.com"},
+		Glue: map[string][]string{
+			"ns2.dev.example.com.": {"192.0.2.54"},
+			"NS1.dev.example.com":  {"192.0.2.53", "2001:0db8::53"},
+		},
+		TTL: 3600,
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := c.Delegate(context.Background(), "example.com", d); err != nil {
+			t.Fatalf("Delegate() error = %v", err)
+		}
+	}
+
+	// Glue is created in order of hostname, regardless of map iteration order.
+	want := `POST /1.0/domains/example.com/records {"data":{"records":[` +
+		`{"name":"dev","type":"NS","content":"ns2.dev.example.com","ttl":3600},` +
+		`{"name":"dev","type":"NS","content":"ns1.dev.example.com","ttl":3600},` +
+		`{"name":"ns1.dev","type":"A","content":"192.0.2.53","ttl":3600},` +
+		`{"name":"ns1.dev","type":"AAAA","content":"2001:db8::53","ttl":3600},` +
+		`{"name":"ns2.dev","type":"A","content":"192.0.2.54","ttl":3600}]}}`
+	for i := range requests {
+		if requests[i] != want {
+			t.Fatalf("request %d = %s, want %s", i, requests[i], want)
+		}
+	}
+	if len(requests) != 20 || len(queried) != 40 {
+		t.Errorf("Delegate() made %d requests and %d nameserver queries, want 20 and 40", len(requests), len(queried))
+	}
+}
+
+func TestClient_DelegateInvalid(t *testing.T) {
+	var queried []string
+	fakeNameservers(t, map[string][]string{}, &queried)
+
+	var req *http.Request
+	c := testServer(t, `{"response":{"serial":"2","changed":[]}}`, &req)
+
+	tests := []struct {
+		name string
+		d    Delegation
+	}{
+		{"no name", Delegation{Nameservers: []string{"ns.example.net"}, SkipValidation: true}},
+		{"no nameservers", Delegation{Name: "dev", SkipValidation: true}},
+		{"glue outside domain", Delegation{Name: "dev", Nameservers: []string{"ns.example.net"}, Glue: map[string][]string{"ns.example.net": {"192.0.2.53"}}, SkipValidation: true}},
+		{"invalid glue address", Delegation{Name: "dev", Nameservers: []string{"ns.dev.example.com"}, Glue: map[string][]string{"ns.dev.example.com": {"192.0.2"}}, SkipValidation: true}},
+		{"not served", Delegation{Name: "dev", Nameservers: []string{"ns.example.net"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Delegate(context.Background(), "example.com", tt.d); err == nil {
+				t.Errorf("Delegate() succeeded, want an error")
+			}
+			if req != nil {
+				t.Errorf("Delegate() made a request: %s %s", req.Method, req.URL)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrDNSSECNotEnabled is returned by DNSSECKeys when the domain isn't signed.
+var ErrDNSSECNotEnabled = errors.New("dnssec is not enabled for domain")
+
+// DSRecord is a delegation signer record, to be published in the parent zone via the domain's registrar.
+type DSRecord struct {
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	Digest     string
+	// Raw is the record in presentation format, as returned by the API. If the record couldn't be parsed, only Raw
+	// is populated.
+	Raw string
+}
+
+// DNSKEYRecord is a public key used to sign a domain.
+type DNSKEYRecord struct {
+	Flags     int
+	Protocol  int
+	Algorithm int
+	PublicKey string
+	// Raw is the record in presentation format, as returned by the API. If the record couldn't be parsed, only Raw
+	// is populated.
+	Raw string
+}
+
+// DNSSECInfo describes the keys a domain is signed with.
+type DNSSECInfo struct {
+	DS     []DSRecord
+	DNSKEY []DNSKEYRecord
+}
+
+// DNSSECKeys retrieves the DS and DNSKEY records of a signed domain, returning ErrDNSSECNotEnabled if the domain
+// isn't signed.
+func (c *Client) DNSSECKeys(ctx context.Context, domain string) (*DNSSECInfo, error) {
+	details, err := c.Domain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if details.DNSSEC == nil || (len(details.DNSSEC.DS) == 0 && len(details.DNSSEC.DNSKEY) == 0) {
+		return nil, fmt.Errorf("%w: %s", ErrDNSSECNotEnabled, strings.ToLower(domain))
+	}
+	return details.DNSSEC, nil
+}
+
+func (d *DNSSECInfo) UnmarshalJSON(data []byte) error {
+	wire := &wireDNSSEC{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*d = *wire.info()
+	return nil
+}
+
+// info parses the records returned by the API. Records that can't be parsed are kept with only Raw populated, so
+// that an unexpected key format doesn't prevent the rest of the domain's details from being read.
+func (w *wireDNSSEC) info() *DNSSECInfo {
+	d := &DNSSECInfo{}
+	for _, raw := range w.DS {
+		record := DSRecord{Raw: raw}
+		if fields, err := rdataFields(raw, "DS", 4); err == nil {
+			record.KeyTag = fields.ints[0]
+			record.Algorithm = fields.ints[1]
+			record.DigestType = fields.ints[2]
+			record.Digest = fields.rest
+		}
+		d.DS = append(d.DS, record)
+	}
+	for _, raw := range w.DNSKEY {
+		record := DNSKEYRecord{Raw: raw}
+		if fields, err := rdataFields(raw, "DNSKEY", 4); err == nil {
+			record.Flags = fields.ints[0]
+			record.Protocol = fields.ints[1]
+			record.Algorithm = fields.ints[2]
+			record.PublicKey = fields.rest
+		}
+		d.DNSKEY = append(d.DNSKEY, record)
+	}
+	return d
+}
+
+type rdata struct {
+	ints []int
+	rest string
+}
+
+// rdataFields splits a record in presentation format, such as "example.com. 3600 IN DS 1234 13 2 ABCD", into the
+// numeric fields following the record type and the remainder of the data, joined without whitespace. The record
+// may omit everything before the type.
+func rdataFields(raw, recordType string, count int) (*rdata, error) {
+	fields := strings.Fields(raw)
+	start := 0
+	for i := range fields {
+		if strings.EqualFold(fields[i], recordType) {
+			start = i + 1
+			break
+		}
+	}
+
+	if len(fields)-start < count {
+		return nil, fmt.Errorf("invalid %s record: %q", recordType, raw)
+	}
+
+	result := &rdata{}
+	for _, field := range fields[start : start+count-1] {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s record: %q", recordType, raw)
+		}
+		result.ints = append(result.ints, n)
+	}
+	result.rest = strings.Join(fields[start+count-1:], "")
+	return result, nil
+}
+
+// EnableDNSSEC turns on DNSSEC signing for a domain. Once the domain is signed, its DS records can be retrieved
+// with DNSSECKeys and published via the domain's registrar.
+func (c *Client) EnableDNSSEC(ctx context.Context, domain string) (*DomainDetails, error) {
+	enabled := true
+	return c.UpdateDomain(ctx, domain, DomainSettings{DNSSEC: &enabled})
+}
+
+// DisableDNSSEC turns off DNSSEC signing for a domain. The domain's DS records should be removed from the parent
+// zone before signing is disabled, otherwise resolvers will fail to validate the domain.
+func (c *Client) DisableDNSSEC(ctx context.Context, domain string) (*DomainDetails, error) {
+	enabled := false
+	return c.UpdateDomain(ctx, domain, DomainSettings{DNSSEC: &enabled})
+}
@@ -0,0 +1,115 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DomainUsersResponse lists the users with access to a domain.
+type DomainUsersResponse struct {
+	// Access maps the e-mail addresses of users with access to the domain to their access level.
+	Access map[string]AccessLevel `json:"access"`
+}
+
+// DomainUsers retrieves the access levels of all users with access to the given domain.
+func (c *Client) DomainUsers(ctx context.Context, domain string) (*DomainUsersResponse, error) {
+	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("domains/%s/access", domain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DomainUsersResponse{}
+	return response, c.codec().Unmarshal(*res.Response, response)
+}
+
+// SetDomainAccess changes the access levels of the given users, keyed by e-mail address. Setting a user's level
+// to LevelNone revokes their access. Users not mentioned are unchanged. The domain's access levels after the
+// change are returned.
+func (c *Client) SetDomainAccess(ctx context.Context, domain string, changes map[string]AccessLevel) (*DomainUsersResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/access", domain), apiRequest{
+		Data: DomainUsersResponse{Access: changes},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DomainUsersResponse{}
+	return response, c.codec().Unmarshal(*res.Response, response)
+}
+
+// GrantDomainAccess gives the user with the given e-mail address the given level of access to a domain.
+func (c *Client) GrantDomainAccess(ctx context.Context, domain, email string, level AccessLevel) (*DomainUsersResponse, error) {
+	return c.SetDomainAccess(ctx, domain, map[string]AccessLevel{email: level})
+}
+
+// RevokeDomainAccess removes all access to a domain from the user with the given e-mail address.
+func (c *Client) RevokeDomainAccess(ctx context.Context, domain, email string) (*DomainUsersResponse, error) {
+	return c.SetDomainAccess(ctx, domain, map[string]AccessLevel{email: LevelNone})
+}
+
+// DomainGrant describes the outcome of granting access to a single domain with GrantDomainsAccess.
+type DomainGrant struct {
+	Domain string
+	// Granted lists the users who now have the requested level of access to the domain.
+	Granted []string
+	// Missing lists the users the API didn't grant access to, for example because they don't have an account.
+	Missing []string
+	// Err is the error returned when changing the domain's access levels, if any.
+	Err error
+}
+
+// GrantDomainsAccess gives each of the users with the given e-mail addresses the given level of access to each of
+// the domains, such as when onboarding a new team. Each domain is changed with a single SetDomainAccess call, and
+// a failure to change one domain doesn't prevent the others from being changed. The returned grants describe the
+// outcome for each domain in order; the error is non-nil if any domain couldn't be changed.
+//
+// The API has no way to invite users, so users who don't yet have an account are reported as missing.
+func (c *Client) GrantDomainsAccess(ctx context.Context, emails, domains []string, level AccessLevel) ([]DomainGrant, error) {
+	changes := make(map[string]AccessLevel, len(emails))
+	for i := range emails {
+		changes[emails[i]] = level
+	}
+
+	grants := make([]DomainGrant, len(domains))
+	failed := 0
+	for i := range domains {
+		grants[i].Domain = domains[i]
+
+		res, err := c.SetDomainAccess(ctx, domains[i], changes)
+		if err != nil {
+			grants[i].Err = err
+			grants[i].Missing = emails
+			failed++
+			continue
+		}
+
+		for j := range emails {
+			if hasAccess(res.Access, emails[j], level) {
+				grants[i].Granted = append(grants[i].Granted, emails[j])
+			} else {
+				grants[i].Missing = append(grants[i].Missing, emails[j])
+			}
+		}
+	}
+
+	if failed > 0 {
+		return grants, fmt.Errorf("unable to grant access to %d of %d domains", failed, len(domains))
+	}
+	return grants, nil
+}
+
+// hasAccess reports whether access gives the user with the given e-mail address the given level of access.
+func hasAccess(access map[string]AccessLevel, email string, level AccessLevel) bool {
+	for user := range access {
+		if strings.EqualFold(user, email) {
+			return access[user] == level
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DomainDetails describes a domain managed by MyDNSHost.
+type DomainDetails struct {
+	Id         int    `json:"id"`
+	Domain     string `json:"domain"`
+	Disabled   bool   `json:"disabled"`
+	DefaultTTL int    `json:"defaultttl,omitempty"`
+	// AliasOf is the name of the domain this domain mirrors the records of, if any.
+	AliasOf string `json:"aliasof,omitempty"`
+	// Access maps the e-mail addresses of users with access to the domain to their access level. It is only
+	// populated by Domain.
+	Access map[string]AccessLevel `json:"access,omitempty"`
+	// VerificationState describes whether the domain's delegation to MyDNSHost has been verified.
+	VerificationState string `json:"verificationstate,omitempty"`
+	// VerificationStateTime is when the verification state was last updated, as a Unix timestamp.
+	VerificationStateTime int `json:"verificationstatetime,omitempty"`
+	// DNSSECEnabled reports whether the domain is signed with DNSSEC.
+	DNSSECEnabled bool `json:"dnssec"`
+	// DNSSEC describes the keys the domain is signed with, if any. It is only populated by Domain.
+	DNSSEC *DNSSECInfo `json:"DNSSEC,omitempty"`
+}
+
+// Domain retrieves the details of a single domain.
+func (c *Client) Domain(ctx context.Context, domain string) (*DomainDetails, error) {
+	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("domains/%s", domain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireDomainDetails{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	return wire.details(), nil
+}
+
+// CreateDomainOpts configures a new domain.
+type CreateDomainOpts struct {
+	// Owner is the ID of the user who should own the domain. If blank, the domain is owned by the current user.
+	// Only administrators may create domains for other users.
+	Owner string `json:"owner,omitempty"`
+}
+
+// CreateDomainResponse describes a newly created domain.
+type CreateDomainResponse struct {
+	DomainDetails
+	// UserAccess is the current user's level of access to the new domain.
+	UserAccess AccessLevel `json:"-"`
+}
+
+// CreateDomain creates a new domain with the given name. The domain starts with no records other than those the
+// API adds by default. If the domain is created but the current user's access to it can't be retrieved, the
+// response is returned along with the error.
+func (c *Client) CreateDomain(ctx context.Context, name string, opts CreateDomainOpts) (*CreateDomainResponse, error) {
+	if err := c.checkFrozen(ctx, name); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, "domains", apiRequest{
+		Data: struct {
+			Domain string `json:"domain"`
+			CreateDomainOpts
+		}{
+			Domain:           name,
+			CreateDomainOpts: opts,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireDomainDetails{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+
+	response := &CreateDomainResponse{DomainDetails: *wire.details()}
+
+	// The creation response doesn't include access levels, so look it up.
+	if response.Domain == "" {
+		response.Domain = name
+	}
+
+	domains, err := c.Domains(ctx)
+	if err != nil {
+		return response, fmt.Errorf("domain %s created, but unable to retrieve access level: %w", response.Domain, err)
+	}
+
+	response.UserAccess = domains[response.Domain]
+	if response.UserAccess == "" {
+		response.UserAccess = LevelNone
+	}
+	return response, nil
+}
+
+// DeleteDomainResponse describes the result of deleting a domain.
+type DeleteDomainResponse struct {
+	Deleted bool `json:"deleted"`
+}
+
+// DeleteDomain permanently deletes a domain and all of its records.
+func (c *Client) DeleteDomain(ctx context.Context, domain string) (*DeleteDomainResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodDelete, fmt.Sprintf("domains/%s", domain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireDeleteDomainResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	return wire.response(), nil
+}
+
+// DomainSettings describes changes to a domain's settings. Fields left nil are not changed.
+type DomainSettings struct {
+	Disabled   *bool `json:"disabled,omitempty"`
+	DefaultTTL *int  `json:"defaultttl,omitempty"`
+	// AliasOf sets the domain this domain mirrors the records of. An empty string removes the alias.
+	AliasOf *string `json:"aliasof,omitempty"`
+	// DNSSEC enables or disables DNSSEC signing of the domain.
+	DNSSEC *bool `json:"dnssec,omitempty"`
+}
+
+// UpdateDomain changes a domain's settings, returning its updated details.
+func (c *Client) UpdateDomain(ctx context.Context, domain string, settings DomainSettings) (*DomainDetails, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s", domain), apiRequest{Data: settings})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireDomainDetails{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	return wire.details(), nil
+}
+
+// SyncDomain asks the API to push a domain's zone back out to the backend nameservers. This can be used if the
+// nameservers appear to be serving different records from those reported by the API. As it doesn't change the
+// domain's records, SyncDomain is allowed while the domain is frozen.
+func (c *Client) SyncDomain(ctx context.Context, domain string) error {
+	_, err := c.request(ctx, http.MethodGet, fmt.Sprintf("domains/%s/sync", domain), nil)
+	return err
+}
@@ -0,0 +1,114 @@
+package mydnshost_go_api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// maxResponseErrorBody is the maximum amount of a response body retained in a ResponseError.
+const maxResponseErrorBody = 4096
+
+// APIError is returned when the API responds to a request with an error. It can be retrieved from errors returned
+// by the client using errors.As.
+type APIError struct {
+	// Message is the error message given by the API.
+	Message string
+	// ErrorData contains additional information about the error, such as which fields failed validation.
+	ErrorData map[string]string
+	// ResponseId is the API's identifier for the response, which may be useful when reporting issues.
+	ResponseId string
+	// Method is the HTTP method of the request, as reported by the API.
+	Method string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Message)
+}
+
+// ErrorHint is a piece of additional information the API gave about an error, parsed from its ErrorData.
+type ErrorHint struct {
+	// Operation is the position of the record operation the hint relates to, for errors returned by ModifyRecords,
+	// or -1 if it doesn't relate to an operation.
+	Operation int
+	// Field is the name of the field the hint relates to, if it relates to a field rather than an operation.
+	Field string
+	// Message is the API's description of the problem.
+	Message string
+}
+
+func (h ErrorHint) String() string {
+	switch {
+	case h.Operation >= 0:
+		return fmt.Sprintf("operation %d: %s", h.Operation, h.Message)
+	case h.Field != "":
+		return fmt.Sprintf("%s: %s", h.Field, h.Message)
+	default:
+		return h.Message
+	}
+}
+
+// Hints parses the error's ErrorData into hints. The API keys ErrorData by the position of the failing operation
+// when rejecting record changes, and by field name otherwise. Hints for operations are returned first, in order,
+// followed by those for fields, sorted by name.
+func (e *APIError) Hints() []ErrorHint {
+	hints := make([]ErrorHint, 0, len(e.ErrorData))
+	for key, message := range e.ErrorData {
+		hint := ErrorHint{Operation: -1, Message: message}
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 {
+			hint.Operation = i
+		} else {
+			hint.Field = key
+		}
+		hints = append(hints, hint)
+	}
+
+	sort.Slice(hints, func(i, j int) bool {
+		a, b := hints[i], hints[j]
+		if (a.Operation >= 0) != (b.Operation >= 0) {
+			return a.Operation >= 0
+		}
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		return a.Field < b.Field
+	})
+	return hints
+}
+
+// ResponseError is returned when the API's response could not be understood: because it could not be decoded, or
+// because it had an unsuccessful HTTP status but no error message.
+type ResponseError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Body is the raw response body, truncated if it was especially long.
+	Body []byte
+	// Err is the reason the response could not be used, if there was one beyond the status code.
+	Err error
+}
+
+func newResponseError(status int, body []byte, err error) *ResponseError {
+	if len(body) > maxResponseErrorBody {
+		body = body[:maxResponseErrorBody]
+	}
+
+	return &ResponseError{
+		StatusCode: status,
+		Body:       append([]byte(nil), body...),
+		Err:        err,
+	}
+}
+
+func (e *ResponseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("unexpected response from API (HTTP %d %s): %v", e.StatusCode, http.StatusText(e.StatusCode), e.Err)
+	}
+	return fmt.Sprintf("unexpected response from API (HTTP %d %s)", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,85 @@
+package mydnshost_go_api
+
+import "time"
+
+// Event is emitted by a Client to its subscribers to describe its activity. Events are one of the *Event types
+// defined in this package, and subscribers should use a type switch to handle those they are interested in.
+type Event interface {
+	event()
+}
+
+// RequestStartedEvent is emitted when the client begins a request to the API.
+type RequestStartedEvent struct {
+	Time   time.Time
+	Method string
+	Route  string
+}
+
+// RequestCompletedEvent is emitted when a request to the API has finished, successfully or not.
+type RequestCompletedEvent struct {
+	Time     time.Time
+	Method   string
+	Route    string
+	Duration time.Duration
+	Err      error
+}
+
+// ZoneChangedEvent is emitted when the client has successfully changed the records of a domain.
+type ZoneChangedEvent struct {
+	Time   time.Time
+	Domain string
+	Serial uint64
+}
+
+func (*RequestStartedEvent) event()   {}
+func (*RequestCompletedEvent) event() {}
+func (*ZoneChangedEvent) event()      {}
+
+// Subscribe returns a channel that will receive all events emitted by the client, and a function to cancel the
+// subscription and close the channel. Events are delivered without blocking the client: if the channel's buffer
+// (of the given size) is full, events are dropped.
+func (c *Client) Subscribe(size int) (<-chan Event, func()) {
+	ch := make(chan Event, size)
+
+	c.mu.Lock()
+	if c.subscribers == nil {
+		c.subscribers = make(map[chan Event]struct{})
+	}
+	c.subscribers[ch] = struct{}{}
+	c.mu.Unlock()
+
+	return ch, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.subscribers[ch]; ok {
+			delete(c.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// emit sends an event to all subscribers.
+func (c *Client) emit(e Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for ch := range c.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// hasSubscribers reports whether anyone is listening for events, so that callers can avoid building events that
+// would be discarded.
+func (c *Client) hasSubscribers() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.subscribers) > 0
+}
+
+func (c *Client) zoneChanged(domain string, serial uint64) {
+	if c.hasSubscribers() {
+		c.emit(&ZoneChangedEvent{Time: c.now(), Domain: domain, Serial: serial})
+	}
+}
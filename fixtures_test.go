@@ -0,0 +1,179 @@
+package mydnshost_go_api
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// decodeFixture decodes the response envelope stored in testdata/responses and returns it.
+func decodeFixture(t *testing.T, name string) *apiResponse {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "responses", name+".json"))
+	if err != nil {
+		t.Fatalf("unable to read fixture: %v", err)
+	}
+
+	response := &apiResponse{}
+	if err := (StandardCodec{}).Unmarshal(data, response); err != nil {
+		t.Fatalf("unable to decode fixture %s: %v", name, err)
+	}
+	return response
+}
+
+func TestResponseFixtures(t *testing.T) {
+	records := &RecordsResponse{
+		Records: []ExistingRecord{
+			{
+				Record:    Record{Name: "", Type: "A", Content: "192.0.2.1", TTL: 3600, Disabled: boolPtr(false)},
+				Id:        10,
+				ChangedAt: 1599999000,
+				ChangedBy: intPtr(1),
+			},
+			{
+				Record:    Record{Name: "", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: intPtr(10), Disabled: boolPtr(false)},
+				Id:        11,
+				ChangedAt: 1599999000,
+			},
+			{
+				Record:    Record{Name: "www", Type: "CNAME", Content: "example.com", TTL: 86400, Disabled: boolPtr(true)},
+				Id:        12,
+				ChangedAt: 1599999100,
+				ChangedBy: intPtr(1),
+			},
+		},
+		HasNS: true,
+	}
+	records.Soa.PrimaryNS = "ns1.mydnshost.co.uk."
+	records.Soa.AdminAddress = "dnsadmin.example.com."
+	records.Soa.Serial = 2020091301
+	records.Soa.Refresh = 86400
+	records.Soa.Retry = 7200
+	records.Soa.Expire = 2419200
+	records.Soa.MinTTL = 60
+
+	userData := &UserDataResponse{}
+	userData.User.Id = "1"
+	userData.User.Email = "user@example.com"
+	userData.User.RealName = "Example User"
+	userData.Access.DomainsRead = true
+	userData.Access.DomainsWrite = true
+	userData.Access.UserRead = true
+
+	tests := []struct {
+		fixture string
+		target  interface{}
+		want    interface{}
+	}{
+		{"ping", &PingResponse{}, &PingResponse{Time: "1599999999"}},
+		{"userdata", &UserDataResponse{}, userData},
+		{"domains", &DomainAccess{}, &DomainAccess{"example.com": LevelOwner, "example.org": LevelWrite, "example.net": LevelRead}},
+		{"records", &RecordsResponse{}, records},
+		{"modify_records", &ModifyRecordsResponse{}, &ModifyRecordsResponse{
+			Serial: 2020091302,
+			Changed: []ChangedRecord{
+				{ExistingRecord: ExistingRecord{
+					Record:    Record{Name: "test", Type: "A", Content: "0.0.0.0", TTL: 84600, Disabled: boolPtr(false)},
+					Id:        13,
+					ChangedAt: 1599999200,
+					ChangedBy: intPtr(1),
+				}},
+				{ExistingRecord: ExistingRecord{
+					Record:    Record{Name: "www", Type: "CNAME", Content: "example.com", TTL: 3600, Disabled: boolPtr(false)},
+					Id:        12,
+					ChangedAt: 1599999200,
+					ChangedBy: intPtr(1),
+				}, Updated: true},
+				{ExistingRecord: ExistingRecord{Id: 10}, Deleted: true},
+			},
+		}},
+		{"named_records", &FindRecordsResponse{}, &FindRecordsResponse{
+			Records: []ExistingRecord{records.Records[2]},
+		}},
+		{"delete_named_records", &DeletedNamedRecordsResponse{}, &DeletedNamedRecordsResponse{Deleted: 2, Serial: 2020091303}},
+		{"domain", &DomainDetails{}, &DomainDetails{
+			Id:                    5,
+			Domain:                "example.com",
+			DefaultTTL:            86400,
+			Access:                map[string]AccessLevel{"user@example.com": LevelOwner, "colleague@example.com": LevelWrite},
+			VerificationState:     "valid",
+			VerificationStateTime: 1599999300,
+			DNSSECEnabled:         true,
+			DNSSEC: &DNSSECInfo{
+				DS: []DSRecord{{
+					KeyTag:     28457,
+					Algorithm:  13,
+					DigestType: 2,
+					Digest:     "4E7A2B3F0D8C1A6E5B9F2C7D3E1A0B4C6D8E9F0A1B2C3D4E5F6A7B8C9D0E1F2A",
+					Raw:        "example.com. IN DS 28457 13 2 4E7A2B3F0D8C1A6E5B9F2C7D3E1A0B4C 6D8E9F0A1B2C3D4E5F6A7B8C9D0E1F2A",
+				}},
+				DNSKEY: []DNSKEYRecord{{
+					Flags:     257,
+					Protocol:  3,
+					Algorithm: 13,
+					PublicKey: "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==",
+					Raw:       "example.com. IN DNSKEY 257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0d xCjjnopKl+GqJxpVXckHAeF+KkxLbxIL fDLUT0rAK9iUzy1L53eKGQ==",
+				}},
+			},
+		}},
+		{"domain_logs", &[]DomainLogEntry{}, &[]DomainLogEntry{
+			{
+				Time:    1599999000,
+				User:    "user@example.com",
+				Action:  "add",
+				Message: "Added record www CNAME",
+				Record: &ExistingRecord{
+					Record:    Record{Name: "www", Type: "CNAME", Content: "example.com", TTL: 86400, Disabled: boolPtr(false)},
+					Id:        12,
+					ChangedAt: 1599999000,
+					ChangedBy: intPtr(1),
+				},
+			},
+			{Time: 1599999100, Action: "update"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			response := decodeFixture(t, tt.fixture)
+			if response.Error != nil {
+				t.Fatalf("fixture has unexpected error: %s", *response.Error)
+			}
+
+			if err := (StandardCodec{}).Unmarshal(*response.Response, tt.target); err != nil {
+				t.Fatalf("unable to decode response: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.target, tt.want) {
+				t.Errorf("decoded response = %+v, want %+v", tt.target, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorFixture(t *testing.T) {
+	response := decodeFixture(t, "error")
+	if response.Error == nil || *response.Error != "Example error message" {
+		t.Errorf("Error = %v, want %q", response.Error, "Example error message")
+	}
+	if response.ErrorData["field"] != "Example error detail" {
+		t.Errorf("ErrorData = %v, want field entry", response.ErrorData)
+	}
+	apiErr := &APIError{Message: *response.Error, ErrorData: response.ErrorData}
+	if hints := apiErr.Hints(); len(hints) != 1 || hints[0].String() != "field: Example error detail" {
+		t.Errorf("Hints() = %+v, want the field hint", hints)
+	}
+	if response.ResponseId != "5f5e1a2b3c4dc" || response.Method != "GET" {
+		t.Errorf("ResponseId, Method = %q, %q", response.ResponseId, response.Method)
+	}
+}
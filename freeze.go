@@ -0,0 +1,60 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFrozen is returned by calls that would change a domain while it is frozen by the client's FreezePolicy.
+var ErrFrozen = errors.New("domain is frozen")
+
+// FreezePolicy prevents the client from making changes to domains, for example during an incident or a change
+// freeze. Every call that modifies a domain or its records checks the policy, and returns an error wrapping
+// ErrFrozen instead of making the change. Contexts created with WithFreezeOverride bypass the policy.
+type FreezePolicy struct {
+	// All freezes every domain.
+	All bool
+	// Domains lists individual domains to freeze.
+	Domains []string
+}
+
+// Frozen reports whether the policy freezes the given domain.
+func (p *FreezePolicy) Frozen(domain string) bool {
+	if p == nil {
+		return false
+	}
+
+	if p.All {
+		return true
+	}
+
+	domain = normaliseHost(domain)
+	for i := range p.Domains {
+		if normaliseHost(p.Domains[i]) == domain {
+			return true
+		}
+	}
+	return false
+}
+
+type freezeOverrideKey struct{}
+
+// WithFreezeOverride returns a context that allows changes to be made to frozen domains. It should only be used
+// where a change has been explicitly approved despite the freeze.
+func WithFreezeOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freezeOverrideKey{}, true)
+}
+
+// checkFrozen returns an error if the domain is frozen and ctx doesn't override the freeze.
+func (c *Client) checkFrozen(ctx context.Context, domain string) error {
+	if !c.Freeze.Frozen(domain) {
+		return nil
+	}
+
+	if override, _ := ctx.Value(freezeOverrideKey{}).(bool); override {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrFrozen, strings.ToLower(domain))
+}
@@ -0,0 +1,92 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrGuardrailExceeded is returned by changes that would delete or disable too much of a zone, or make too many
+// changes at once.
+var ErrGuardrailExceeded = errors.New("change exceeds guardrails")
+
+// Guardrails limits the size of changes made to a zone, so that a bad template, an empty source zone or an
+// overly broad DisableName can't wipe out a production zone. They apply to every change made through
+// ModifyRecords, including those made by DisableName, ReplicateZone and the templates package, and to zone files
+// loaded by ImportZone. Changes that exceed the limits return an error wrapping ErrGuardrailExceeded instead of
+// being made. Contexts created with WithGuardrailOverride bypass the limits.
+type Guardrails struct {
+	// MaxDeletePercent is the largest proportion of a zone's records, as a percentage, that a change may delete or
+	// disable. If zero, deletions aren't limited.
+	MaxDeletePercent int
+	// MaxOperations is the largest number of operations a change may contain. If zero, changes aren't limited.
+	MaxOperations int
+}
+
+type guardrailOverrideKey struct{}
+
+// WithGuardrailOverride returns a context that allows changes to exceed the client's guardrails. It should only be
+// used where a change has been explicitly approved despite its size.
+func WithGuardrailOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, guardrailOverrideKey{}, true)
+}
+
+// guardrails returns the guardrails that apply to a change made with ctx, or nil if there are none.
+func (c *Client) guardrails(ctx context.Context) *Guardrails {
+	if override, _ := ctx.Value(guardrailOverrideKey{}).(bool); override {
+		return nil
+	}
+	return c.Guardrails
+}
+
+// checkGuardrails returns an error wrapping ErrGuardrailExceeded if the operations would exceed the client's
+// guardrails. The domain's records are only retrieved if the operations delete or disable any.
+func (c *Client) checkGuardrails(ctx context.Context, domain string, operations []RecordOperation) error {
+	guardrails := c.guardrails(ctx)
+	if guardrails == nil {
+		return nil
+	}
+
+	removed := removedRecords(operations)
+	if removed == 0 || guardrails.MaxDeletePercent == 0 {
+		return guardrails.check(domain, len(operations), removed, 0)
+	}
+
+	current, err := c.Records(ctx, domain)
+	if err != nil {
+		return err
+	}
+	return guardrails.check(domain, len(operations), removed, len(current.Records))
+}
+
+// check returns an error wrapping ErrGuardrailExceeded if a change of the given number of operations, removing
+// the given number of records from a zone of the given size, exceeds the guardrails.
+func (g *Guardrails) check(domain string, operations, removed, size int) error {
+	if g.MaxOperations > 0 && operations > g.MaxOperations {
+		return fmt.Errorf("%w: %d operations to %s, limit is %d", ErrGuardrailExceeded, operations,
+			strings.ToLower(domain), g.MaxOperations)
+	}
+
+	if g.MaxDeletePercent > 0 && removed*100 > g.MaxDeletePercent*size {
+		return fmt.Errorf("%w: removing %d of %d records in %s, limit is %d%%", ErrGuardrailExceeded, removed,
+			size, strings.ToLower(domain), g.MaxDeletePercent)
+	}
+	return nil
+}
+
+// removedRecords counts the operations that delete or disable a record.
+func removedRecords(operations []RecordOperation) int {
+	removed := 0
+	for i := range operations {
+		op := decodedOperation{}
+		if err := json.Unmarshal(operations[i], &op); err != nil {
+			continue
+		}
+		if op.Delete || (op.Disabled != nil && *op.Disabled) {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,107 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// DomainHook is a webhook that the API calls whenever a domain's zone changes.
+type DomainHook struct {
+	Id  int    `json:"id"`
+	URL string `json:"url"`
+	// Password is sent with each call to the hook so the receiver can authenticate it.
+	Password string `json:"password,omitempty"`
+	Disabled bool   `json:"disabled"`
+	Created  int    `json:"created"`
+	LastUsed int    `json:"lastused"`
+}
+
+// DomainHookSettings describes changes to a domain hook. Fields left nil are not changed.
+type DomainHookSettings struct {
+	URL      *string `json:"url,omitempty"`
+	Password *string `json:"password,omitempty"`
+	Disabled *bool   `json:"disabled,omitempty"`
+}
+
+// DomainHooks lists the webhooks configured for a domain.
+func (c *Client) DomainHooks(ctx context.Context, domain string) ([]DomainHook, error) {
+	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("domains/%s/hooks", domain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if emptyList(*res.Response) {
+		return []DomainHook{}, nil
+	}
+
+	// The API returns hooks keyed by their ID.
+	byId := make(map[string]wireDomainHook)
+	if err := c.codec().Unmarshal(*res.Response, &byId); err != nil {
+		return nil, err
+	}
+
+	hooks := make([]DomainHook, 0, len(byId))
+	for id := range byId {
+		wire := byId[id]
+		hooks = append(hooks, wire.hook())
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].Id < hooks[j].Id
+	})
+	return hooks, nil
+}
+
+// CreateDomainHook adds a webhook to a domain that will be called with the given password whenever the domain's
+// zone changes.
+func (c *Client) CreateDomainHook(ctx context.Context, domain, url, password string) (*DomainHook, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/hooks", domain), apiRequest{
+		Data: DomainHookSettings{URL: &url, Password: &password},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireDomainHook{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+
+	hook := wire.hook()
+	return &hook, nil
+}
+
+// UpdateDomainHook changes the settings of one of a domain's webhooks, returning the updated hook.
+func (c *Client) UpdateDomainHook(ctx context.Context, domain string, id int, settings DomainHookSettings) (*DomainHook, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/hooks/%d", domain, id), apiRequest{Data: settings})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireDomainHook{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+
+	hook := wire.hook()
+	return &hook, nil
+}
+
+// DeleteDomainHook removes a webhook from a domain.
+func (c *Client) DeleteDomainHook(ctx context.Context, domain string, id int) error {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return err
+	}
+
+	_, err := c.request(ctx, http.MethodDelete, fmt.Sprintf("domains/%s/hooks/%d", domain, id), nil)
+	return err
+}
@@ -0,0 +1,112 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ModifyRecordsIdempotent behaves like ModifyRecords, but if the request fails in a way that leaves it unclear
+// whether the changes were applied (e.g. the connection dropped before a response was received, or a gateway in front
+// of the API returned a 502 or 504 response) it re-fetches the domain's records, discards any operations that have
+// already taken effect, and retries the remainder once.
+//
+// Creations are considered to have taken effect if a record with the same name, type and content exists;
+// modifications if the record with the given ID already has all of the supplied values; and deletions if no record
+// with the given ID exists. If every operation had already been applied, the returned response contains the
+// domain's current serial and no changed records.
+//
+// If ctx is cancelled while checking which operations were applied, the context's error is returned and the
+// remaining operations are not retried.
+func (c *Client) ModifyRecordsIdempotent(ctx context.Context, domain string, operations ...RecordOperation) (*ModifyRecordsResponse, error) {
+	res, err := c.ModifyRecords(ctx, domain, operations...)
+	if err == nil || !ambiguousFailure(err) || ctx.Err() != nil {
+		return res, err
+	}
+
+	current, rerr := c.Records(ctx, domain)
+	if rerr != nil {
+		return nil, err
+	}
+
+	var remaining []RecordOperation
+	for i := range operations {
+		if !operationApplied(current.Records, operations[i]) {
+			remaining = append(remaining, operations[i])
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(remaining) == 0 {
+		return &ModifyRecordsResponse{Serial: current.Soa.Serial}, nil
+	}
+
+	return c.ModifyRecords(ctx, domain, remaining...)
+}
+
+// ambiguousFailure checks if an error leaves it unclear whether the request reached the API: either no response was
+// received, or a gateway reported that the API failed to respond.
+func ambiguousFailure(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+
+	var responseErr *ResponseError
+	return errors.As(err, &responseErr) &&
+		(responseErr.StatusCode == http.StatusBadGateway || responseErr.StatusCode == http.StatusGatewayTimeout)
+}
+
+// decodedOperation is the union of all fields that may be present in a RecordOperation.
+type decodedOperation struct {
+	ExistingRecord
+	Delete bool `json:"delete"`
+}
+
+// operationApplied determines whether the given operation appears to have already been applied to the records.
+func operationApplied(records []ExistingRecord, operation RecordOperation) bool {
+	op := decodedOperation{}
+	if err := json.Unmarshal(operation, &op); err != nil {
+		return false
+	}
+
+	if op.Id == 0 {
+		for i := range records {
+			if sameRecord(records[i].Record, op.Record) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range records {
+		if records[i].Id == op.Id {
+			return !op.Delete && recordMatches(records[i].Record, op.Record)
+		}
+	}
+	return op.Delete
+}
+
+// sameRecord checks if two records have the same name, type, content and priority.
+func sameRecord(a, b Record) bool {
+	return strings.EqualFold(a.Name, b.Name) &&
+		strings.EqualFold(a.Type, b.Type) &&
+		a.Content == b.Content &&
+		(b.Priority == nil || (a.Priority != nil && *a.Priority == *b.Priority))
+}
+
+// recordMatches checks if all fields populated in want have the same value in got.
+func recordMatches(got, want Record) bool {
+	return (want.Name == "" || strings.EqualFold(got.Name, want.Name)) &&
+		(want.Type == "" || strings.EqualFold(got.Type, want.Type)) &&
+		(want.Content == "" || got.Content == want.Content) &&
+		(want.TTL == 0 || got.TTL == want.TTL) &&
+		(want.Priority == nil || (got.Priority != nil && *got.Priority == *want.Priority)) &&
+		(want.Disabled == nil || (got.Disabled != nil && *got.Disabled == *want.Disabled))
+}
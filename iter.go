@@ -0,0 +1,73 @@
+//go:build go1.23
+
+package mydnshost_go_api
+
+import (
+	"context"
+	"iter"
+	"sort"
+)
+
+// All returns an iterator over the domains and access levels in d, in order of domain name.
+func (d DomainAccess) All() iter.Seq2[string, AccessLevel] {
+	return func(yield func(string, AccessLevel) bool) {
+		names := make([]string, 0, len(d))
+		for name := range d {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !yield(name, d[name]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the records in the response.
+func (r *RecordsResponse) All() iter.Seq[ExistingRecord] {
+	return func(yield func(ExistingRecord) bool) {
+		for i := range r.Records {
+			if !yield(r.Records[i]) {
+				return
+			}
+		}
+	}
+}
+
+// DomainsSeq returns an iterator over the names of the domains the current user has access to, in name order. The
+// domains are retrieved when iteration starts; if that fails, the error is yielded once and iteration stops.
+func (c *Client) DomainsSeq(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		domains, err := c.Domains(ctx)
+		if err != nil {
+			yield("", err)
+			return
+		}
+
+		for name := range domains.All() {
+			if !yield(name, nil) {
+				return
+			}
+		}
+	}
+}
+
+// RecordsSeq returns an iterator over the records of the given domain. The records are retrieved when iteration
+// starts; if that fails, the error is yielded once and iteration stops.
+func (c *Client) RecordsSeq(ctx context.Context, domain string) iter.Seq2[ExistingRecord, error] {
+	return func(yield func(ExistingRecord, error) bool) {
+		res, err := c.Records(ctx, domain)
+		if err != nil {
+			yield(ExistingRecord{}, err)
+			return
+		}
+
+		for record := range res.All() {
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
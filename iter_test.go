@@ -0,0 +1,43 @@
+//go:build go1.23
+
+package mydnshost_go_api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClient_DomainsSeq(t *testing.T) {
+	c := testServer(t, `{"response":{"example.org":"read","example.com":"owner","example.net":"write"}}`, nil)
+
+	var names []string
+	for name, err := range c.DomainsSeq(context.Background()) {
+		if err != nil {
+			t.Fatalf("DomainsSeq() error = %v", err)
+		}
+		names = append(names, name)
+		if len(names) == 2 {
+			break
+		}
+	}
+
+	if len(names) != 2 || names[0] != "example.com" || names[1] != "example.net" {
+		t.Errorf("DomainsSeq() = %v, want [example.com example.net]", names)
+	}
+}
+
+func TestClient_RecordsSeqError(t *testing.T) {
+	c := testServer(t, `{"error":"Unknown domain"}`, nil)
+
+	calls := 0
+	for _, err := range c.RecordsSeq(context.Background(), "example.com") {
+		calls++
+		if err == nil {
+			t.Errorf("RecordsSeq() error = nil, want error")
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("RecordsSeq() yielded %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,40 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DomainLogEntry is a single entry in a domain's change log.
+type DomainLogEntry struct {
+	// Time is when the change was made, as a Unix timestamp.
+	Time int `json:"time"`
+	// User is the e-mail address of the user who made the change, if known.
+	User string `json:"user,omitempty"`
+	// Action describes the kind of change, such as "add", "update" or "delete".
+	Action string `json:"action"`
+	// Message is a human-readable description of the change.
+	Message string `json:"message,omitempty"`
+	// Record is the record affected by the change, if any.
+	Record *ExistingRecord `json:"record,omitempty"`
+}
+
+// DomainLogs retrieves the log of changes made to a domain and its records, oldest first.
+func (c *Client) DomainLogs(ctx context.Context, domain string) ([]DomainLogEntry, error) {
+	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("domains/%s/logs", domain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var wire []wireDomainLogEntry
+	if err := c.codec().Unmarshal(*res.Response, &wire); err != nil {
+		return nil, err
+	}
+
+	entries := make([]DomainLogEntry, len(wire))
+	for i := range wire {
+		entries[i] = wire[i].entry()
+	}
+	return entries, nil
+}
@@ -0,0 +1,94 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// MaintenanceManifest lists the records disabled by DisableName, so that they can later be re-enabled with
+// EnableManifest. It can be serialised to JSON to persist it between runs.
+type MaintenanceManifest struct {
+	Domain  string           `json:"domain"`
+	Name    string           `json:"name"`
+	Serial  uint64           `json:"serial"`
+	Records []ExistingRecord `json:"records"`
+}
+
+// DisableName disables (without deleting) every record with the given name, and every record in the subtree below
+// it. For example, disabling "api" will also disable "v1.api". Records that are already disabled are left alone and
+// are not included in the returned manifest, so that EnableManifest will not enable them. The name must not be
+// empty, as every record in the domain is below the apex.
+func (c *Client) DisableName(ctx context.Context, domain, name string) (*MaintenanceManifest, error) {
+	if name == "" {
+		return nil, errors.New("a name is required; disabling the apex would disable the whole domain")
+	}
+
+	current, err := c.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &MaintenanceManifest{
+		Domain: domain,
+		Name:   name,
+		Serial: current.Soa.Serial,
+	}
+
+	disabled := true
+	var operations []RecordOperation
+	for i := range current.Records {
+		r := current.Records[i]
+		if inSubtree(r.Name, name) && (r.Disabled == nil || !*r.Disabled) {
+			manifest.Records = append(manifest.Records, r)
+			operations = append(operations, ModifyRecord(r.Id, Record{Disabled: &disabled}))
+		}
+	}
+
+	if len(operations) == 0 {
+		return manifest, nil
+	}
+
+	res, err := c.ModifyRecords(ctx, domain, operations...)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Serial = res.Serial
+	return manifest, nil
+}
+
+// EnableManifest re-enables the records listed in a manifest returned by DisableName. Records that have since been
+// deleted, or that have already been re-enabled, are skipped.
+func (c *Client) EnableManifest(ctx context.Context, manifest *MaintenanceManifest) (*ModifyRecordsResponse, error) {
+	current, err := c.Records(ctx, manifest.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[int]bool, len(manifest.Records))
+	for i := range manifest.Records {
+		wanted[manifest.Records[i].Id] = true
+	}
+
+	enabled := false
+	var operations []RecordOperation
+	for i := range current.Records {
+		r := current.Records[i]
+		if wanted[r.Id] && r.Disabled != nil && *r.Disabled {
+			operations = append(operations, ModifyRecord(r.Id, Record{Disabled: &enabled}))
+		}
+	}
+
+	if len(operations) == 0 {
+		return &ModifyRecordsResponse{Serial: current.Soa.Serial}, nil
+	}
+
+	return c.ModifyRecords(ctx, manifest.Domain, operations...)
+}
+
+// inSubtree checks if the record name is equal to or below the given name.
+func inSubtree(name, parent string) bool {
+	name, parent = strings.ToLower(name), strings.ToLower(parent)
+	return name == parent || strings.HasSuffix(name, "."+parent)
+}
@@ -0,0 +1,143 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recordServer starts a server that holds a domain's records, serving them for GET requests and applying
+// changes to their disabled state for POST requests. The POSTed operations are stored in posts.
+func recordServer(t *testing.T, records map[int]ExistingRecord, posts *[][]decodedOperation) *Client {
+	t.Helper()
+
+	serial := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			var list []ExistingRecord
+			for id := 1; id <= len(records); id++ {
+				list = append(list, records[id])
+			}
+			body, _ := json.Marshal(list)
+			_, _ = fmt.Fprintf(w, `{"response":{"records":%s,"soa":{"serial":%d}}}`, body, serial)
+			return
+		}
+
+		request := struct {
+			Data struct {
+				Records []decodedOperation `json:"records"`
+			} `json:"data"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("unable to decode request: %v", err)
+		}
+		*posts = append(*posts, request.Data.Records)
+
+		for _, op := range request.Data.Records {
+			record := records[op.Id]
+			record.Disabled = op.Disabled
+			records[op.Id] = record
+		}
+		serial++
+		_, _ = fmt.Fprintf(w, `{"response":{"serial":%d,"changed":[]}}`, serial)
+	}))
+	t.Cleanup(server.Close)
+
+	return &Client{BaseURL: server.URL + "/1.0/"}
+}
+
+func TestClient_DisableName(t *testing.T) {
+	records := map[int]ExistingRecord{
+		1: {Id: 1, Record: Record{Name: "api", Type: "A", Content: "192.0.2.1", Disabled: boolPtr(false)}},
+		2: {Id: 2, Record: Record{Name: "v1.API", Type: "A", Content: "192.0.2.2", Disabled: boolPtr(false)}},
+		3: {Id: 3, Record: Record{Name: "old.api", Type: "A", Content: "192.0.2.3", Disabled: boolPtr(true)}},
+		4: {Id: 4, Record: Record{Name: "apix", Type: "A", Content: "192.0.2.4", Disabled: boolPtr(false)}},
+	}
+	var posts [][]decodedOperation
+	c := recordServer(t, records, &posts)
+
+	manifest, err := c.DisableName(context.Background(), "example.com", "api")
+	if err != nil {
+		t.Fatalf("DisableName() error = %v", err)
+	}
+
+	if len(manifest.Records) != 2 || manifest.Records[0].Id != 1 || manifest.Records[1].Id != 2 || manifest.Serial != 2 {
+		t.Errorf("DisableName() manifest = %+v, want records 1 and 2 at serial 2", manifest)
+	}
+	if got := disabledRecords(records); got != "1 2 3" {
+		t.Errorf("disabled records after DisableName() = %s, want 1 2 3", got)
+	}
+
+	// Record 3 was already disabled, so stays disabled.
+	if _, err := c.EnableManifest(context.Background(), manifest); err != nil {
+		t.Fatalf("EnableManifest() error = %v", err)
+	}
+	if got := disabledRecords(records); got != "3" {
+		t.Errorf("disabled records after EnableManifest() = %s, want 3", got)
+	}
+
+	// Enabling again makes no further changes.
+	if _, err := c.EnableManifest(context.Background(), manifest); err != nil {
+		t.Fatalf("EnableManifest() error = %v", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("made %d changes, want 2", len(posts))
+	}
+}
+
+func disabledRecords(records map[int]ExistingRecord) string {
+	var ids []string
+	for id := 1; id <= len(records); id++ {
+		if disabled := records[id].Disabled; disabled != nil && *disabled {
+			ids = append(ids, fmt.Sprint(id))
+		}
+	}
+	return strings.Join(ids, " ")
+}
+
+func TestClient_DisableName_Guardrails(t *testing.T) {
+	records := map[int]ExistingRecord{
+		1: {Id: 1, Record: Record{Name: "api", Type: "A", Content: "192.0.2.1"}},
+		2: {Id: 2, Record: Record{Name: "v1.api", Type: "A", Content: "192.0.2.2"}},
+		3: {Id: 3, Record: Record{Name: "www", Type: "A", Content: "192.0.2.3"}},
+	}
+	var posts [][]decodedOperation
+	c := recordServer(t, records, &posts)
+	c.Guardrails = &Guardrails{MaxDeletePercent: 50}
+
+	if _, err := c.DisableName(context.Background(), "example.com", "api"); !errors.Is(err, ErrGuardrailExceeded) {
+		t.Fatalf("DisableName() of most of the zone error = %v, want ErrGuardrailExceeded", err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("DisableName() made %d changes despite exceeding the guardrails", len(posts))
+	}
+
+	if _, err := c.DisableName(context.Background(), "example.com", "www"); err != nil {
+		t.Fatalf("DisableName() of one record error = %v", err)
+	}
+	if len(posts) != 1 || len(posts[0]) != 1 || posts[0][0].Id != 3 {
+		t.Errorf("DisableName() made changes %+v, want only record 3 disabled", posts)
+	}
+}
+
+func TestClient_DisableName_Apex(t *testing.T) {
+	records := map[int]ExistingRecord{
+		1: {Id: 1, Record: Record{Name: "", Type: "A", Content: "192.0.2.1"}},
+		2: {Id: 2, Record: Record{Name: "www", Type: "A", Content: "192.0.2.2"}},
+	}
+	var posts [][]decodedOperation
+	c := recordServer(t, records, &posts)
+
+	if _, err := c.DisableName(context.Background(), "example.com", ""); err == nil {
+		t.Fatalf("DisableName() of the apex succeeded, want an error")
+	}
+	if len(posts) != 0 || disabledRecords(records) != "" {
+		t.Errorf("DisableName() of the apex made %d changes, want none", len(posts))
+	}
+}
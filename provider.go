@@ -0,0 +1,18 @@
+package mydnshost_go_api
+
+import "context"
+
+// ZoneProvider is the minimal set of operations needed to read and change a domain's records. Client implements
+// it, and the package's provider-independent helpers, such as CompareZones and the templates package, accept any
+// ZoneProvider, so they can be reused with other DNS hosts by implementing it on top of their APIs.
+//
+// Implementations should return records and operations in the forms used by this package: records as they would be
+// returned by the MyDNSHost API, and operations as built by CreateRecord, ModifyRecord and DeleteRecord.
+type ZoneProvider interface {
+	// Records retrieves all records of the given domain.
+	Records(ctx context.Context, domain string) (*RecordsResponse, error)
+	// ModifyRecords applies the given operations to the records of the domain.
+	ModifyRecords(ctx context.Context, domain string, operations ...RecordOperation) (*ModifyRecordsResponse, error)
+}
+
+var _ ZoneProvider = (*Client)(nil)
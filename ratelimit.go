@@ -0,0 +1,112 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultRateLimitDelay is how long to wait after being rate limited if the API doesn't say.
+const defaultRateLimitDelay = time.Second
+
+// RateLimitError is returned when the API rejects a request because the client has been rate limited.
+type RateLimitError struct {
+	// RetryAfter is how long the API asked the client to wait, or zero if it didn't specify.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited by API, retry after %s", e.RetryAfter)
+	}
+	return "rate limited by API"
+}
+
+// RateLimitState describes the most recent rate limiting applied to a client by the API.
+type RateLimitState struct {
+	// LimitedAt is when the client was last rate limited, or the zero time if it never has been.
+	LimitedAt time.Time
+	// Until is when the API indicated requests may resume.
+	Until time.Time
+	// Count is the total number of requests that have been rate limited.
+	Count int
+}
+
+// Limited reports whether the client is still expected to be rate limited at the given time.
+func (s RateLimitState) Limited(now time.Time) bool {
+	return now.Before(s.Until)
+}
+
+// RateLimitedEvent is emitted when a request is rejected because the client has been rate limited.
+type RateLimitedEvent struct {
+	Time       time.Time
+	Method     string
+	Route      string
+	RetryAfter time.Duration
+}
+
+func (*RateLimitedEvent) event() {}
+
+// RateLimit returns the client's current rate limit state.
+func (c *Client) RateLimit() RateLimitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit
+}
+
+// rateLimited records that a request was rate limited and builds the error to return for it.
+func (c *Client) rateLimited(method, route string, res *http.Response) *RateLimitError {
+	now := c.now()
+	err := &RateLimitError{RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), now)}
+
+	delay := err.RetryAfter
+	if delay == 0 {
+		delay = defaultRateLimitDelay
+	}
+
+	c.mu.Lock()
+	c.rateLimit.LimitedAt = now
+	c.rateLimit.Until = now.Add(delay)
+	c.rateLimit.Count++
+	c.mu.Unlock()
+
+	if c.hasSubscribers() {
+		c.emit(&RateLimitedEvent{Time: now, Method: method, Route: route, RetryAfter: err.RetryAfter})
+	}
+	return err
+}
+
+// rateLimitDelay determines how long to wait before retrying a rate limited request, and whether there's enough
+// time left before ctx's deadline to do so.
+func (c *Client) rateLimitDelay(ctx context.Context, err *RateLimitError) (time.Duration, bool) {
+	delay := err.RetryAfter
+	if delay == 0 {
+		delay = defaultRateLimitDelay
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && c.now().Add(delay).After(deadline) {
+		return 0, false
+	}
+	return delay, true
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which may be a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
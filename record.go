@@ -0,0 +1,89 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Record retrieves a single record of a domain by its ID.
+func (c *Client) Record(ctx context.Context, domain string, id int) (*ExistingRecord, error) {
+	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("domains/%s/records/%d", domain, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var wire wireRecord
+	if err := c.codec().Unmarshal(*res.Response, &wire); err != nil {
+		return nil, err
+	}
+
+	record := wire.existingRecord()
+	return &record, nil
+}
+
+// UpdateRecordResponse describes a record changed by UpdateRecord.
+type UpdateRecordResponse struct {
+	ExistingRecord
+	// Serial is the domain's serial number after the change.
+	Serial uint64 `json:"serial"`
+}
+
+// UpdateRecord replaces the record with the given ID, returning the updated record. It is an alternative to
+// calling ModifyRecords with a single ModifyRecord operation.
+func (c *Client) UpdateRecord(ctx context.Context, domain string, id int, record Record) (*UpdateRecordResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+	if err := c.checkChangeRate(domain, recordByID(id)); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/records/%d", domain, id), apiRequest{Data: record})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireUpdateRecordResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	response := wire.response()
+
+	c.zoneChanged(domain, response.Serial)
+	return response, nil
+}
+
+// DeleteRecordResponse describes the result of deleting a single record.
+type DeleteRecordResponse struct {
+	Deleted bool `json:"deleted"`
+	// Serial is the domain's serial number after the change.
+	Serial uint64 `json:"serial"`
+}
+
+// DeleteRecordByID deletes the record with the given ID. It is an alternative to calling ModifyRecords with a
+// single DeleteRecord operation.
+func (c *Client) DeleteRecordByID(ctx context.Context, domain string, id int) (*DeleteRecordResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+	if err := c.checkChangeRate(domain, recordByID(id)); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodDelete, fmt.Sprintf("domains/%s/records/%d", domain, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireDeleteRecordResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	response := wire.response()
+
+	if response.Deleted {
+		c.zoneChanged(domain, response.Serial)
+	}
+	return response, nil
+}
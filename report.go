@@ -0,0 +1,150 @@
+package mydnshost_go_api
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// reportRow is a single change in a ZoneDiff report.
+type reportRow struct {
+	Change  string
+	Name    string
+	Type    string
+	Content string
+	TTL     string
+}
+
+// reportData is the content of a ZoneDiff report, shared by each output format.
+type reportData struct {
+	Domain   string
+	Added    int
+	Removed  int
+	Changed  int
+	Rows     []reportRow
+	Warnings []string
+}
+
+func (d *ZoneDiff) report() *reportData {
+	data := &reportData{
+		Domain:  d.Domain,
+		Added:   len(d.Missing),
+		Removed: len(d.Extra),
+		Changed: len(d.Changed),
+	}
+
+	for i := range d.Missing {
+		data.Rows = append(data.Rows, newReportRow("Add", d.Missing[i].Record, strconv.Itoa(d.Missing[i].TTL)))
+	}
+	for i := range d.Changed {
+		source, target := d.Changed[i].Source.Record, d.Changed[i].Target.Record
+		ttl := strconv.Itoa(source.TTL)
+		if source.TTL != target.TTL {
+			ttl = fmt.Sprintf("%d → %d", target.TTL, source.TTL)
+		}
+		data.Rows = append(data.Rows, newReportRow("Change", source, ttl))
+	}
+	for i := range d.Extra {
+		data.Rows = append(data.Rows, newReportRow("Remove", d.Extra[i].Record, strconv.Itoa(d.Extra[i].TTL)))
+	}
+
+	removedNS := 0
+	for i := range d.Extra {
+		if strings.EqualFold(d.Extra[i].Type, "NS") && d.Extra[i].Name == "" {
+			removedNS++
+		}
+	}
+	if removedNS > 0 {
+		data.Warnings = append(data.Warnings, fmt.Sprintf("%d NS record(s) at the zone apex will be removed, which may break delegation.", removedNS))
+	}
+	return data
+}
+
+func newReportRow(change string, record Record, ttl string) reportRow {
+	name := record.Name
+	if name == "" {
+		name = "@"
+	}
+
+	content := record.Content
+	if record.Priority != nil {
+		content = fmt.Sprintf("%d %s", *record.Priority, content)
+	}
+	if record.Disabled != nil && *record.Disabled {
+		content += " (disabled)"
+	}
+
+	return reportRow{Change: change, Name: name, Type: strings.ToUpper(record.Type), Content: content, TTL: ttl}
+}
+
+// WriteMarkdown writes a Markdown summary of the differences, suitable for reviewing proposed changes before they
+// are applied.
+func (d *ZoneDiff) WriteMarkdown(w io.Writer) error {
+	data := d.report()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Changes to %s\n\n", data.Domain)
+	fmt.Fprintf(&b, "| Added | Changed | Removed |\n|---|---|---|\n| %d | %d | %d |\n", data.Added, data.Changed, data.Removed)
+
+	if len(data.Warnings) > 0 {
+		b.WriteString("\n## Warnings\n\n")
+		for _, warning := range data.Warnings {
+			fmt.Fprintf(&b, "- %s\n", warning)
+		}
+	}
+
+	if len(data.Rows) > 0 {
+		b.WriteString("\n## Records\n\n| Change | Name | Type | Content | TTL |\n|---|---|---|---|---|\n")
+		for _, row := range data.Rows {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", row.Change, markdownCell(row.Name), row.Type, markdownCell(row.Content), row.TTL)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML writes a standalone HTML page summarising the differences, suitable for sharing with reviewers.
+func (d *ZoneDiff) WriteHTML(w io.Writer) error {
+	return reportTemplate.Execute(w, d.report())
+}
+
+// markdownCell escapes text for use in a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Changes to {{.Domain}}</title>
+</head>
+<body>
+<h1>Changes to {{.Domain}}</h1>
+<table>
+<tr><th>Added</th><th>Changed</th><th>Removed</th></tr>
+<tr><td>{{.Added}}</td><td>{{.Changed}}</td><td>{{.Removed}}</td></tr>
+</table>
+{{- if .Warnings}}
+<h2>Warnings</h2>
+<ul>
+{{- range .Warnings}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Rows}}
+<h2>Records</h2>
+<table>
+<tr><th>Change</th><th>Name</th><th>Type</th><th>Content</th><th>TTL</th></tr>
+{{- range .Rows}}
+<tr><td>{{.Change}}</td><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Content}}</td><td>{{.TTL}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,287 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultResolverTTL = time.Minute
+	maxCNAMEDepth      = 8
+)
+
+// Resolver answers DNS lookups for domains managed by MyDNSHost using the records held by the API, rather than
+// querying live DNS. This allows names to be resolved exactly as the zone defines them, regardless of propagation or
+// caching. Disabled records are ignored, and wildcard records are used for names that don't exist in the zone, as
+// described in RFC 4592.
+type Resolver struct {
+	Client *Client
+	// TTL is how long zone data and the list of domains are cached. Defaults to one minute.
+	TTL time.Duration
+
+	mu        sync.Mutex
+	domains   []string
+	domainsAt time.Time
+	zones     map[string]*cachedZone
+}
+
+type cachedZone struct {
+	fetched time.Time
+	records []ExistingRecord
+}
+
+// LookupRecords returns the enabled records of the given type for the fully-qualified name. If recordType is blank,
+// records of every type are returned.
+func (r *Resolver) LookupRecords(ctx context.Context, name, recordType string) ([]ExistingRecord, error) {
+	name = normaliseHost(name)
+	domain, err := r.domainFor(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := r.zone(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	relative := ""
+	if name != domain {
+		relative = strings.TrimSuffix(name, "."+domain)
+	}
+
+	matches := matchRecords(records, relative, recordType)
+	if len(matches) == 0 && !nameExists(records, relative) {
+		matches = matchRecords(records, wildcardFor(records, relative), recordType)
+	}
+
+	if len(matches) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return matches, nil
+}
+
+// LookupHost returns the IPv4 and IPv6 addresses of the name, following CNAMEs within managed domains.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	target, err := r.LookupCNAME(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []string
+	for _, recordType := range []string{"A", "AAAA"} {
+		records, err := r.LookupRecords(ctx, target, recordType)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		for i := range records {
+			addresses = append(addresses, records[i].Content)
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addresses, nil
+}
+
+// LookupCNAME follows CNAME records from the given name for as long as they point into managed domains, and returns
+// the final name. If the name has no CNAME record, it is returned unchanged.
+func (r *Resolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	name := normaliseHost(host)
+	for i := 0; i < maxCNAMEDepth; i++ {
+		records, err := r.LookupRecords(ctx, name, "CNAME")
+		if isNotFound(err) {
+			return name, nil
+		} else if err != nil {
+			return "", err
+		}
+		name = normaliseHost(records[0].Content)
+	}
+	return "", &net.DNSError{Err: "too many CNAMEs", Name: host}
+}
+
+// LookupTXT returns the TXT records for the name.
+func (r *Resolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, err := r.LookupRecords(ctx, name, "TXT")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, len(records))
+	for i := range records {
+		res[i] = records[i].Content
+	}
+	return res, nil
+}
+
+// LookupMX returns the MX records for the name, sorted by priority.
+func (r *Resolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	records, err := r.LookupRecords(ctx, name, "MX")
+	if err != nil {
+		return nil, err
+	}
+
+	SortByPriority(records)
+	res := make([]*net.MX, len(records))
+	for i := range records {
+		res[i] = &net.MX{Host: normaliseHost(records[i].Content) + "."}
+		if records[i].Priority != nil {
+			res[i].Pref = uint16(*records[i].Priority)
+		}
+	}
+	return res, nil
+}
+
+// LookupNS returns the NS records for the name.
+func (r *Resolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	records, err := r.LookupRecords(ctx, name, "NS")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*net.NS, len(records))
+	for i := range records {
+		res[i] = &net.NS{Host: normaliseHost(records[i].Content) + "."}
+	}
+	return res, nil
+}
+
+// Flush discards all cached data.
+func (r *Resolver) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.domains = nil
+	r.zones = nil
+}
+
+func (r *Resolver) ttl() time.Duration {
+	if r.TTL <= 0 {
+		return defaultResolverTTL
+	}
+	return r.TTL
+}
+
+// domainFor finds the most specific managed domain containing the name.
+func (r *Resolver) domainFor(ctx context.Context, name string) (string, error) {
+	r.mu.Lock()
+	domains := r.domains
+	if r.Client.now().Sub(r.domainsAt) > r.ttl() {
+		domains = nil
+	}
+	r.mu.Unlock()
+
+	if domains == nil {
+		access, err := r.Client.Domains(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		for domain := range access.Readable() {
+			domains = append(domains, normaliseHost(domain))
+		}
+		// Longest first, so that the most specific domain is found first.
+		sort.Slice(domains, func(i, j int) bool {
+			return len(domains[i]) > len(domains[j])
+		})
+
+		r.mu.Lock()
+		r.domains = domains
+		r.domainsAt = r.Client.now()
+		r.mu.Unlock()
+	}
+
+	for _, domain := range domains {
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return domain, nil
+		}
+	}
+	return "", &net.DNSError{Err: "name is not in a managed domain", Name: name, IsNotFound: true}
+}
+
+// zone returns the records of the domain, from the cache if they are fresh enough.
+func (r *Resolver) zone(ctx context.Context, domain string) ([]ExistingRecord, error) {
+	r.mu.Lock()
+	cached := r.zones[domain]
+	r.mu.Unlock()
+
+	if cached != nil && r.Client.now().Sub(cached.fetched) <= r.ttl() {
+		return cached.records, nil
+	}
+
+	res, err := r.Client.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.zones == nil {
+		r.zones = make(map[string]*cachedZone)
+	}
+	r.zones[domain] = &cachedZone{fetched: r.Client.now(), records: res.Records}
+	r.mu.Unlock()
+
+	return res.Records, nil
+}
+
+// matchRecords returns the enabled records with the given relative name and (if not blank) type.
+func matchRecords(records []ExistingRecord, name, recordType string) []ExistingRecord {
+	var res []ExistingRecord
+	for i := range records {
+		r := records[i]
+		if (r.Disabled == nil || !*r.Disabled) &&
+			strings.EqualFold(r.Name, name) &&
+			(recordType == "" || strings.EqualFold(r.Type, recordType)) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// nameExists checks if the relative name exists in the zone, which prevents wildcards applying to it. A name exists
+// if it has enabled records, or if enabled records exist below it (an empty non-terminal). The apex always exists.
+func nameExists(records []ExistingRecord, name string) bool {
+	if name == "" {
+		return true
+	}
+
+	for i := range records {
+		r := records[i]
+		if r.Disabled != nil && *r.Disabled {
+			continue
+		}
+		if recordName := strings.ToLower(r.Name); recordName == name || strings.HasSuffix(recordName, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardFor gives the wildcard name that would cover the given relative name, which must not exist. As described
+// in RFC 4592, this is the wildcard at the name's closest encloser: its nearest ancestor that exists in the zone.
+func wildcardFor(records []ExistingRecord, name string) string {
+	for name != "" {
+		if i := strings.Index(name, "."); i >= 0 {
+			name = name[i+1:]
+		} else {
+			name = ""
+		}
+
+		if nameExists(records, name) {
+			break
+		}
+	}
+
+	if name == "" {
+		return "*"
+	}
+	return "*." + name
+}
+
+func isNotFound(err error) bool {
+	dnsErr, ok := err.(*net.DNSError)
+	return ok && dnsErr.IsNotFound
+}
@@ -0,0 +1,120 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/domains"):
+			_, _ = w.Write([]byte(`{"response":{"example.com":"read","sub.example.com":"read"}}`))
+		case strings.HasSuffix(r.URL.Path, "/domains/example.com/records"):
+			_, _ = w.Write([]byte(`{"response":{"records":[
+				{"id":1,"name":"","type":"A","content":"192.0.2.1"},
+				{"id":2,"name":"www","type":"CNAME","content":"example.com."},
+				{"id":3,"name":"","type":"MX","content":"mx2.example.com","priority":20},
+				{"id":4,"name":"","type":"MX","content":"mx1.example.com","priority":10},
+				{"id":5,"name":"*","type":"A","content":"192.0.2.99"},
+				{"id":6,"name":"old","type":"A","content":"192.0.2.2","disabled":true},
+				{"id":7,"name":"","type":"TXT","content":"v=spf1 -all"}
+			]}}`))
+		case strings.HasSuffix(r.URL.Path, "/domains/sub.example.com/records"):
+			_, _ = w.Write([]byte(`{"response":{"records":[{"id":8,"name":"","type":"AAAA","content":"2001:db8::1"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := &Resolver{Client: &Client{BaseURL: server.URL}}
+	ctx := context.Background()
+
+	hosts := map[string][]string{
+		"www.example.com":          {"192.0.2.1"},
+		"anything.example.com":     {"192.0.2.99"},
+		"sub.example.com":          {"2001:db8::1"},
+		"old.example.com":          {"192.0.2.99"},
+		"deeper.thing.example.com": {"192.0.2.99"},
+	}
+	for host, want := range hosts {
+		got, err := r.LookupHost(ctx, host)
+		if want == nil {
+			if !isNotFound(err) {
+				t.Errorf("LookupHost(%q) = %v, %v; want not found", host, got, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("LookupHost(%q) = %v, %v; want %v", host, got, err, want)
+		}
+	}
+
+	mx, err := r.LookupMX(ctx, "example.com")
+	if err != nil || len(mx) != 2 || mx[0].Host != "mx1.example.com." || mx[0].Pref != 10 {
+		t.Errorf("LookupMX() = %v, %v", mx, err)
+	}
+
+	txt, err := r.LookupTXT(ctx, "example.com.")
+	if err != nil || !reflect.DeepEqual(txt, []string{"v=spf1 -all"}) {
+		t.Errorf("LookupTXT() = %v, %v", txt, err)
+	}
+
+	if _, err := r.LookupHost(ctx, "example.org"); !isNotFound(err) {
+		t.Errorf("LookupHost() of unmanaged domain error = %v, want not found", err)
+	}
+}
+
+func TestResolver_ClosestEncloser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/domains"):
+			_, _ = w.Write([]byte(`{"response":{"example.com":"read"}}`))
+		case strings.HasSuffix(r.URL.Path, "/domains/example.com/records"):
+			_, _ = w.Write([]byte(`{"response":{"records":[
+				{"id":1,"name":"*","type":"A","content":"192.0.2.1"},
+				{"id":2,"name":"*.api","type":"A","content":"192.0.2.2"},
+				{"id":3,"name":"v1.api","type":"A","content":"192.0.2.3"},
+				{"id":4,"name":"host.ent","type":"A","content":"192.0.2.4"},
+				{"id":5,"name":"host.gone","type":"A","content":"192.0.2.5","disabled":true}
+			]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := &Resolver{Client: &Client{BaseURL: server.URL}}
+	ctx := context.Background()
+
+	hosts := map[string][]string{
+		// The closest encloser of x.y.api is api, so its wildcard is used rather than *.y.api or the apex's.
+		"x.y.api.example.com": {"192.0.2.2"},
+		"v2.api.example.com":  {"192.0.2.2"},
+		"v1.api.example.com":  {"192.0.2.3"},
+		// An empty non-terminal exists, so it is not covered by the apex wildcard, and is the closest encloser of
+		// names below it, which have no wildcard of their own.
+		"ent.example.com":       nil,
+		"other.ent.example.com": nil,
+		// Disabled records don't make a name exist.
+		"gone.example.com":     {"192.0.2.1"},
+		"sub.gone.example.com": {"192.0.2.1"},
+	}
+	for host, want := range hosts {
+		got, err := r.LookupHost(ctx, host)
+		if want == nil {
+			if !isNotFound(err) {
+				t.Errorf("LookupHost(%q) = %v, %v; want not found", host, got, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("LookupHost(%q) = %v, %v; want %v", host, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package mydnshost_go_api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy configures how a Client retries requests that fail due to transient network errors or server errors
+// (5xx responses). Only requests using idempotent HTTP methods are retried unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is attempted, including the first. Values of one or
+	// less disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, which doubles for each subsequent retry. Defaults to 500ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries. Defaults to 30 seconds.
+	MaxDelay time.Duration
+	// RetryNonIdempotent allows POST requests (such as ModifyRecords) to be retried. This may cause changes to be
+	// applied twice if a request succeeded but its response was lost; ModifyRecordsIdempotent handles that case.
+	RetryNonIdempotent bool
+}
+
+// RetryScheduledEvent is emitted when a failed request will be retried after a delay.
+type RetryScheduledEvent struct {
+	Time    time.Time
+	Method  string
+	Route   string
+	Attempt int
+	Delay   time.Duration
+	Err     error
+}
+
+func (*RetryScheduledEvent) event() {}
+
+// shouldRetry determines whether a request that failed after the given number of attempts should be tried again.
+func (p *RetryPolicy) shouldRetry(method string, attempt int, status int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts || err == nil {
+		return false
+	}
+
+	if !p.RetryNonIdempotent && !idempotent(method) {
+		return false
+	}
+
+	var urlErr *url.Error
+	return status >= 500 || (status == 0 && errors.As(err, &urlErr))
+}
+
+// backoff calculates the delay before the retry following the given attempt, using exponential backoff with jitter.
+func (p *RetryPolicy) backoff(attempt int, random Random) time.Duration {
+	base, max := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	if max <= 0 {
+		max = defaultRetryMaxDelay
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	half := delay / 2
+	return half + time.Duration(random.Int63n(int64(half)+1))
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
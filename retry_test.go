@@ -0,0 +1,38 @@
+package mydnshost_go_api
+
+import (
+	"testing"
+	"time"
+)
+
+type fixedRandom int64
+
+func (f fixedRandom) Int63n(n int64) int64 {
+	if int64(f) >= n {
+		return n - 1
+	}
+	return int64(f)
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		random  fixedRandom
+		want    time.Duration
+	}{
+		{1, 0, 50 * time.Millisecond},
+		{1, fixedRandom(time.Hour), 100 * time.Millisecond},
+		{2, 0, 100 * time.Millisecond},
+		{3, 0, 200 * time.Millisecond},
+		{5, 0, 500 * time.Millisecond},
+		{10, fixedRandom(time.Hour), time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := p.backoff(tt.attempt, tt.random); got != tt.want {
+			t.Errorf("backoff(%d, %d) = %v, want %v", tt.attempt, tt.random, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package mydnshost_go_api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Conventional priorities for mail exchangers, for use with MXRecord.
+const (
+	PriorityPrimaryMX   = 10
+	PrioritySecondaryMX = 20
+)
+
+// MXRecord creates a Record describing a mail exchanger for the given name at the given priority.
+func MXRecord(name, target string, priority int) Record {
+	return Record{
+		Name:     name,
+		Type:     "MX",
+		Content:  target,
+		Priority: &priority,
+	}
+}
+
+// SRVRecord creates a Record describing a service located at the given target and port. MyDNSHost stores the
+// priority separately from the rest of the SRV data, which is held in the content as "weight port target".
+func SRVRecord(name string, priority, weight, port int, target string) Record {
+	return Record{
+		Name:     name,
+		Type:     "SRV",
+		Content:  fmt.Sprintf("%d %d %s", weight, port, target),
+		Priority: &priority,
+	}
+}
+
+// RRSetKey identifies a set of records that share the same name and type.
+type RRSetKey struct {
+	Name string
+	Type string
+}
+
+// GroupRRSets groups the given records by their name and type. Names and types are compared case-insensitively
+// and are lower-cased in the returned keys. Records within each group are sorted using SortByPriority.
+func GroupRRSets(records []ExistingRecord) map[RRSetKey][]ExistingRecord {
+	res := make(map[RRSetKey][]ExistingRecord)
+	for i := range records {
+		key := RRSetKey{
+			Name: strings.ToLower(records[i].Name),
+			Type: strings.ToLower(records[i].Type),
+		}
+		res[key] = append(res[key], records[i])
+	}
+
+	for key := range res {
+		SortByPriority(res[key])
+	}
+	return res
+}
+
+// SortByPriority sorts records in place so the most preferred come first: by ascending priority, then for SRV
+// records by descending weight, and finally by content. Records without a priority sort after those with one.
+func SortByPriority(records []ExistingRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Priority == nil || b.Priority == nil {
+			if (a.Priority == nil) != (b.Priority == nil) {
+				return b.Priority == nil
+			}
+		} else if *a.Priority != *b.Priority {
+			return *a.Priority < *b.Priority
+		}
+
+		if wa, wb := srvWeight(a.Record), srvWeight(b.Record); wa != wb {
+			return wa > wb
+		}
+
+		return a.Content < b.Content
+	})
+}
+
+// srvWeight extracts the weight from the content of an SRV record, or returns 0 for any other record.
+func srvWeight(r Record) int {
+	if !strings.EqualFold(r.Type, "SRV") {
+		return 0
+	}
+
+	fields := strings.Fields(r.Content)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	weight, _ := strconv.Atoi(fields[0])
+	return weight
+}
@@ -0,0 +1,100 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRecordHelpers_Encode(t *testing.T) {
+	var req *http.Request
+	c := testServer(t, `{"response":{"serial":"2","changed":[]}}`, &req)
+
+	_, err := c.ModifyRecords(context.Background(), "example.com",
+		CreateRecord(MXRecord("", "mail.example.com", PriorityPrimaryMX)),
+		CreateRecord(SRVRecord("_sip._tcp", 10, 60, 5060, "sip.example.com")))
+	if err != nil {
+		t.Fatalf("ModifyRecords() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/1.0/domains/example.com/records" {
+		t.Errorf("request = %s %s, want POST /1.0/domains/example.com/records", req.Method, req.URL.Path)
+	}
+	want := `{"data":{"records":[` +
+		`{"type":"MX","content":"mail.example.com","priority":10},` +
+		`{"name":"_sip._tcp","type":"SRV","content":"60 5060 sip.example.com","priority":10}]}}`
+	if body := requestBody(t, req); body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+}
+
+func TestGroupRRSets(t *testing.T) {
+	c := testServer(t, `{"response":{"records":[
+		{"id":"1","name":"_sip._tcp","type":"SRV","content":"10 5060 sip2.example.com","priority":"10"},
+		{"id":"2","name":"","type":"MX","content":"backup.example.com","priority":"20"},
+		{"id":"3","name":"_SIP._tcp","type":"srv","content":"60 5060 sip1.example.com","priority":"10"},
+		{"id":"4","name":"","type":"MX","content":"mail.example.com","priority":"10"},
+		{"id":"5","name":"_sip._tcp","type":"SRV","content":"0 5060 fallback.example.com","priority":"20"}
+	],"soa":{"serial":"2"}}}`, nil)
+
+	res, err := c.Records(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Records() error = %v", err)
+	}
+
+	sets := GroupRRSets(res.Records)
+	tests := []struct {
+		key  RRSetKey
+		want []int
+	}{
+		{RRSetKey{Name: "", Type: "mx"}, []int{4, 2}},
+		{RRSetKey{Name: "_sip._tcp", Type: "srv"}, []int{3, 1, 5}},
+	}
+	if len(sets) != len(tests) {
+		t.Errorf("GroupRRSets() returned %d sets, want %d", len(sets), len(tests))
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, r := range sets[tt.key] {
+			got = append(got, r.Id)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("GroupRRSets()[%v] = %v, want %v", tt.key, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("GroupRRSets()[%v] = %v, want %v", tt.key, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	records := []ExistingRecord{
+		{Id: 1, Record: Record{Type: "TXT", Content: "b"}},
+		{Id: 2, Record: Record{Type: "MX", Content: "mx3.example.com", Priority: intPtr(20)}},
+		{Id: 3, Record: Record{Type: "SRV", Content: "10 5060 sip2.example.com", Priority: intPtr(10)}},
+		{Id: 4, Record: Record{Type: "TXT", Content: "a"}},
+		{Id: 5, Record: Record{Type: "SRV", Content: "60 5060 sip1.example.com", Priority: intPtr(10)}},
+		{Id: 6, Record: Record{Type: "MX", Content: "mx2.example.com", Priority: intPtr(10)}},
+		{Id: 7, Record: Record{Type: "MX", Content: "mx1.example.com", Priority: intPtr(10)}},
+		{Id: 8, Record: Record{Type: "TXT", Content: "a"}},
+		{Id: 9, Record: Record{Type: "MX", Content: "mx0.example.com", Priority: intPtr(0)}},
+	}
+
+	SortByPriority(records)
+
+	// Priority 0 is a real priority and sorts first; nil priorities sort last. Ties are broken by SRV weight, then
+	// content, and records that are fully equal keep their original order.
+	want := []int{9, 5, 3, 7, 6, 2, 4, 8, 1}
+	got := make([]int, len(records))
+	for i := range records {
+		got[i] = records[i].Id
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByPriority() order = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package mydnshost_go_api
+
+import (
+	"strconv"
+	"time"
+)
+
+// serialHalf is 2^31, half of the 32-bit serial number space.
+const serialHalf = 1 << 31
+
+// CompareSerials compares two zone serial numbers using RFC 1982 serial number arithmetic, which allows serials to
+// wrap around from 2^32-1 to 0. It returns -1 if a is older than b, 1 if a is newer than b, and 0 if they are equal.
+// ok is false if the comparison is undefined, which happens when the serials are exactly 2^31 apart.
+func CompareSerials(a, b uint64) (result int, ok bool) {
+	x, y := uint32(a), uint32(b)
+	switch {
+	case x == y:
+		return 0, true
+	case y-x == serialHalf:
+		return 0, false
+	case y-x < serialHalf:
+		return -1, true
+	default:
+		return 1, true
+	}
+}
+
+// SerialNewer reports whether serial a is newer than serial b under RFC 1982 serial number arithmetic.
+func SerialNewer(a, b uint64) bool {
+	result, ok := CompareSerials(a, b)
+	return ok && result > 0
+}
+
+// AddSerial adds n to a serial number, wrapping around at 2^32 as described by RFC 1982. n must be less than 2^31
+// for the result to compare as newer than the original serial.
+func AddSerial(serial uint64, n uint32) uint64 {
+	return uint64(uint32(serial) + n)
+}
+
+// SerialWrapped reports whether moving from serial old to serial new wraps around the serial number space, i.e.
+// new is newer than old but numerically smaller. Some secondary nameservers handle this poorly.
+func SerialWrapped(old, new uint64) bool {
+	return SerialNewer(new, old) && uint32(new) < uint32(old)
+}
+
+// NextSerial returns the serial number that should follow the given one using the common YYYYMMDDnn convention: the
+// first serial for the given date if the current serial is older, and otherwise the current serial plus one.
+func NextSerial(serial uint64, now time.Time) uint64 {
+	dated, _ := strconv.ParseUint(now.Format("20060102")+"00", 10, 64)
+	if SerialNewer(dated, serial) {
+		return dated
+	}
+	return AddSerial(serial, 1)
+}
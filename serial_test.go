@@ -0,0 +1,54 @@
+package mydnshost_go_api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareSerials(t *testing.T) {
+	tests := []struct {
+		a, b   uint64
+		want   int
+		wantOk bool
+	}{
+		{1, 1, 0, true},
+		{1, 2, -1, true},
+		{2, 1, 1, true},
+		{0, 4294967295, 1, true},
+		{4294967295, 0, -1, true},
+		{0, 2147483648, 0, false},
+		{2020091301, 2020091302, -1, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := CompareSerials(tt.a, tt.b)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("CompareSerials(%d, %d) = %d, %v, want %d, %v", tt.a, tt.b, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestSerialWrapped(t *testing.T) {
+	if !SerialWrapped(4294967295, AddSerial(4294967295, 1)) {
+		t.Errorf("SerialWrapped(4294967295, 0) = false, want true")
+	}
+	if SerialWrapped(1, 2) {
+		t.Errorf("SerialWrapped(1, 2) = true, want false")
+	}
+}
+
+func TestNextSerial(t *testing.T) {
+	now := time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC)
+	tests := map[uint64]uint64{
+		2020091205: 2020091300,
+		2020091300: 2020091301,
+		2020091399: 2020091400,
+		1:          2020091300,
+	}
+
+	for serial, want := range tests {
+		if got := NextSerial(serial, now); got != want {
+			t.Errorf("NextSerial(%d) = %d, want %d", serial, got, want)
+		}
+	}
+}
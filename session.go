@@ -0,0 +1,214 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTwoFactorRequired is returned by Login if the account has two-factor authentication enabled. The login
+	// should be retried with LoginWithCode.
+	ErrTwoFactorRequired = errors.New("two-factor authentication code required")
+	// ErrInvalidTwoFactorCode is returned by LoginWithCode if the API rejects the two-factor code.
+	ErrInvalidTwoFactorCode = errors.New("two-factor authentication code rejected")
+)
+
+// Login creates a new session using the given e-mail address and password, and returns an authenticator that uses
+// it. The client's own authenticator is not used or changed; to use the session for subsequent requests, assign the
+// result to the client's Authenticator.
+//
+// If the account has two-factor authentication enabled, the returned error will match ErrTwoFactorRequired when
+// checked with errors.Is.
+func (c *Client) Login(ctx context.Context, email, password string) (*SessionAuthenticator, error) {
+	return c.LoginWithCode(ctx, email, password, "")
+}
+
+// LoginWithCode creates a new session in the same way as Login, additionally supplying a two-factor authentication
+// code. If the code is not accepted, the returned error will match ErrInvalidTwoFactorCode.
+func (c *Client) LoginWithCode(ctx context.Context, email, password, code string) (*SessionAuthenticator, error) {
+	auth := &passwordAuthenticator{user: email, password: password, code: code}
+	res, err := c.requestWithAuth(ctx, auth, http.MethodGet, "session", nil)
+	if err != nil {
+		return nil, classifyLoginError(err, code != "")
+	}
+
+	response := &SessionAuthenticator{}
+	if err := c.codec().Unmarshal(*res.Response, response); err != nil {
+		return nil, err
+	}
+
+	if response.SessionID == "" {
+		return nil, errors.New("API did not return a session ID")
+	}
+	return response, nil
+}
+
+// twoFactorError wraps an APIError relating to two-factor authentication so that it matches one of the
+// two-factor sentinel errors, while still allowing the APIError to be retrieved with errors.As.
+type twoFactorError struct {
+	err    *APIError
+	reason error
+}
+
+func (e *twoFactorError) Error() string {
+	return e.reason.Error() + ": " + e.err.Error()
+}
+
+func (e *twoFactorError) Unwrap() error {
+	return e.err
+}
+
+func (e *twoFactorError) Is(target error) bool {
+	return target == e.reason
+}
+
+// classifyLoginError identifies API errors caused by a missing or incorrect two-factor code. The API doesn't
+// distinguish these with a code, so errors that mention 2FA in their message or data are assumed to be about it.
+func classifyLoginError(err error, codeSupplied bool) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !mentionsTwoFactor(apiErr) {
+		return err
+	}
+
+	reason := ErrTwoFactorRequired
+	if codeSupplied {
+		reason = ErrInvalidTwoFactorCode
+	}
+	return &twoFactorError{err: apiErr, reason: reason}
+}
+
+func mentionsTwoFactor(e *APIError) bool {
+	if strings.Contains(strings.ToLower(e.Message), "2fa") {
+		return true
+	}
+
+	for k, v := range e.ErrorData {
+		if strings.Contains(strings.ToLower(k), "2fa") || strings.Contains(strings.ToLower(v), "2fa") {
+			return true
+		}
+	}
+	return false
+}
+
+// RefreshableAuthenticator is a ClientAuthenticator whose credentials expire and can be renewed. The Client
+// refreshes it before making a request if it has no credentials, and again if the API rejects a request with a 401
+// or 403 status, retrying the request once.
+type RefreshableAuthenticator interface {
+	ClientAuthenticator
+	// Authenticated reports whether the authenticator currently holds credentials that don't need refreshing.
+	Authenticated() bool
+	// Refresh obtains new credentials, using the given client to make any API calls.
+	Refresh(ctx context.Context, c *Client) error
+}
+
+// LoginAuthenticator authenticates using a session that it creates by logging in with an e-mail address and
+// password, and re-creates automatically whenever the session expires.
+type LoginAuthenticator struct {
+	Email    string
+	Password string
+	// Code, if set, is called to obtain a two-factor authentication code each time a login is required.
+	Code func(ctx context.Context) (string, error)
+	// Lifetime is how long a session remains valid after it is created. If zero, sessions are assumed to last until
+	// the API rejects them, and Expiry and KeepAlive are unavailable.
+	Lifetime time.Duration
+	// RenewBefore is how long before a session expires that it should be renewed. Defaults to 1 minute, and is
+	// limited to half of Lifetime so that short sessions are still used before being renewed.
+	RenewBefore time.Duration
+
+	mu      sync.Mutex
+	session *SessionAuthenticator
+	clock   Clock
+	expiry  time.Time
+}
+
+func (a *LoginAuthenticator) AddHeaders(r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.session != nil {
+		a.session.AddHeaders(r)
+	}
+}
+
+// Authenticated reports whether the authenticator holds a session that isn't due to be renewed.
+func (a *LoginAuthenticator) Authenticated() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.session == nil {
+		return false
+	}
+	return a.expiry.IsZero() || a.clock.Now().Before(a.renewAt())
+}
+
+func (a *LoginAuthenticator) Refresh(ctx context.Context, c *Client) error {
+	code := ""
+	if a.Code != nil {
+		var err error
+		if code, err = a.Code(ctx); err != nil {
+			return err
+		}
+	}
+
+	session, err := c.LoginWithCode(ctx, a.Email, a.Password, code)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.session = session
+	a.clock = c.clock()
+	a.expiry = time.Time{}
+	if a.Lifetime > 0 {
+		a.expiry = a.clock.Now().Add(a.Lifetime)
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// Expiry returns when the current session expires, or the zero time if there is no session or Lifetime isn't set.
+func (a *LoginAuthenticator) Expiry() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.expiry
+}
+
+// KeepAlive renews the session RenewBefore its expiry, logging in first if there is no session, until ctx is
+// cancelled or a renewal fails. It is intended to be run in the background by long-running processes, so that
+// requests don't have to wait for a login. Lifetime must be set.
+func (a *LoginAuthenticator) KeepAlive(ctx context.Context, c *Client) error {
+	if a.Lifetime <= 0 {
+		return errors.New("session lifetime is not set")
+	}
+
+	for {
+		a.mu.Lock()
+		wait := time.Duration(0)
+		if a.session != nil && !a.expiry.IsZero() {
+			wait = a.renewAt().Sub(c.now())
+		}
+		a.mu.Unlock()
+
+		if !c.sleep(ctx, wait) {
+			return ctx.Err()
+		}
+
+		if err := a.Refresh(ctx, c); err != nil {
+			return err
+		}
+	}
+}
+
+// renewAt returns when the current session should be renewed. The caller must hold a.mu.
+func (a *LoginAuthenticator) renewAt() time.Time {
+	margin := a.RenewBefore
+	if margin <= 0 {
+		margin = time.Minute
+	}
+	if limit := a.Lifetime / 2; margin > limit {
+		margin = limit
+	}
+	return a.expiry.Add(-margin)
+}
@@ -0,0 +1,106 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// UpdateSOA changes a domain's SOA data. Fields left blank in soa keep their current values, which are retrieved
+// with Records first. The serial is managed by the API and is not changed; the new serial is returned in the
+// response.
+//
+// If soa.Serial is set, it is treated as the serial the SOA was read at, and the update is only made if the domain's
+// current serial matches it, as with ModifyRecordsAtSerial. Otherwise ErrConcurrentModification is returned.
+func (c *Client) UpdateSOA(ctx context.Context, domain string, soa SOA) (*ModifyRecordsResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	if !soaComplete(soa) || soa.Serial != 0 {
+		current, err := c.Records(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+		if soa.Serial != 0 {
+			if err := checkSerial(soa.Serial, current.Soa.Serial); err != nil {
+				return nil, err
+			}
+		}
+		soa = mergeSOA(soa, current.Soa)
+	}
+
+	if !soaComplete(soa) {
+		return nil, errors.New("SOA requires a primary nameserver, admin address, refresh, retry, expiry and minimum TTL")
+	}
+
+	if err := c.checkChangeRate(domain, recordByName("", "SOA")); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/records", domain), apiRequest{
+		Data: struct {
+			Soa soaUpdate `json:"soa"`
+		}{
+			Soa: soaUpdate{
+				PrimaryNS:    soa.PrimaryNS,
+				AdminAddress: soa.AdminAddress,
+				Refresh:      soa.Refresh,
+				Retry:        soa.Retry,
+				Expire:       soa.Expire,
+				MinTTL:       soa.MinTTL,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireModifyRecordsResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	response := wire.response()
+
+	c.zoneChanged(domain, response.Serial)
+	return response, nil
+}
+
+// soaComplete reports whether every field of soa other than the serial is populated.
+func soaComplete(soa SOA) bool {
+	return soa.PrimaryNS != "" && soa.AdminAddress != "" && soa.Refresh != 0 && soa.Retry != 0 && soa.Expire != 0 && soa.MinTTL != 0
+}
+
+// mergeSOA fills the blank fields of soa from current.
+func mergeSOA(soa, current SOA) SOA {
+	if soa.PrimaryNS == "" {
+		soa.PrimaryNS = current.PrimaryNS
+	}
+	if soa.AdminAddress == "" {
+		soa.AdminAddress = current.AdminAddress
+	}
+	if soa.Refresh == 0 {
+		soa.Refresh = current.Refresh
+	}
+	if soa.Retry == 0 {
+		soa.Retry = current.Retry
+	}
+	if soa.Expire == 0 {
+		soa.Expire = current.Expire
+	}
+	if soa.MinTTL == 0 {
+		soa.MinTTL = current.MinTTL
+	}
+	return soa
+}
+
+// soaUpdate is the SOA data sent by UpdateSOA, which excludes the serial.
+type soaUpdate struct {
+	PrimaryNS    string `json:"primaryNS"`
+	AdminAddress string `json:"adminAddress"`
+	Refresh      uint64 `json:"refresh"`
+	Retry        uint64 `json:"retry"`
+	Expire       uint64 `json:"expire"`
+	MinTTL       uint64 `json:"min_ttl"`
+}
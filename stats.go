@@ -0,0 +1,60 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// StatsPoint is a single datapoint of a statistics time series.
+type StatsPoint struct {
+	// Time is the start of the period the datapoint covers, as a Unix timestamp.
+	Time  int64   `json:"time"`
+	Value float64 `json:"value"`
+}
+
+// DomainStatsOpts configures the statistics returned by DomainStats.
+type DomainStatsOpts struct {
+	// Type is the statistic to retrieve, such as "queries-per-rrtype". If blank, the API's default is used.
+	Type string
+	// Period is how far back from now the statistics should cover. If zero, the API's default is used.
+	Period time.Duration
+	// Interval is the length of time each datapoint should cover. If zero, the API's default is used.
+	Interval time.Duration
+}
+
+// DomainStatsResponse contains query statistics for a domain.
+type DomainStatsResponse struct {
+	// Stats maps the name of each series (for example, a record type) to its datapoints in time order.
+	Stats map[string][]StatsPoint `json:"stats"`
+}
+
+// DomainStats retrieves query statistics for a domain.
+func (c *Client) DomainStats(ctx context.Context, domain string, opts DomainStatsOpts) (*DomainStatsResponse, error) {
+	query := url.Values{}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+	if opts.Period > 0 {
+		query.Set("time", strconv.FormatInt(int64(opts.Period/time.Second), 10))
+	}
+	if opts.Interval > 0 {
+		query.Set("interval", strconv.FormatInt(int64(opts.Interval/time.Second), 10))
+	}
+
+	route := fmt.Sprintf("domains/%s/stats", domain)
+	if len(query) > 0 {
+		route += "?" + query.Encode()
+	}
+
+	res, err := c.request(ctx, http.MethodGet, route, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DomainStatsResponse{}
+	return response, c.codec().Unmarshal(*res.Response, response)
+}
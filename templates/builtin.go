@@ -0,0 +1,53 @@
+package templates
+
+import mydnshost "github.com/mydnshost/mydnshost-go-api"
+
+// BasicWeb points the apex of a domain and its www subdomain at a single IPv4 address.
+var BasicWeb = &Template{
+	Name:        "basic-web",
+	Description: "Apex and www A records for a single web server",
+	Parameters:  []string{"ipv4"},
+	Records: []mydnshost.Record{
+		{Name: "", Type: "A", Content: "{{.ipv4}}", TTL: 3600},
+		{Name: "www", Type: "A", Content: "{{.ipv4}}", TTL: 3600},
+	},
+}
+
+// GoogleWorkspace configures mail delivery and SPF for Google Workspace.
+var GoogleWorkspace = &Template{
+	Name:        "google-workspace",
+	Description: "MX and SPF records for Google Workspace",
+	Records: []mydnshost.Record{
+		{Name: "", Type: "MX", Content: "smtp.google.com", TTL: 3600, Priority: priority(1)},
+		{Name: "", Type: "TXT", Content: "v=spf1 include:_spf.google.com ~all", TTL: 3600},
+	},
+}
+
+// Microsoft365 configures mail delivery, autodiscover and SPF for Microsoft 365. The mx parameter is the tenant's
+// mail host as shown in the admin centre, e.g. "example-com.mail.protection.outlook.com".
+var Microsoft365 = &Template{
+	Name:        "microsoft-365",
+	Description: "MX, autodiscover and SPF records for Microsoft 365",
+	Parameters:  []string{"mx"},
+	Records: []mydnshost.Record{
+		{Name: "", Type: "MX", Content: "{{.mx}}", TTL: 3600, Priority: priority(0)},
+		{Name: "autodiscover", Type: "CNAME", Content: "autodiscover.outlook.com", TTL: 3600},
+		{Name: "", Type: "TXT", Content: "v=spf1 include:spf.protection.outlook.com -all", TTL: 3600},
+	},
+}
+
+// Fastmail configures mail delivery, SPF and DKIM for Fastmail. The domain parameter is the fully-qualified name of
+// the domain being configured, which Fastmail includes in its DKIM key names.
+var Fastmail = &Template{
+	Name:        "fastmail",
+	Description: "MX, SPF and DKIM records for Fastmail",
+	Parameters:  []string{"domain"},
+	Records: []mydnshost.Record{
+		{Name: "", Type: "MX", Content: "in1-smtp.messagingengine.com", TTL: 3600, Priority: priority(10)},
+		{Name: "", Type: "MX", Content: "in2-smtp.messagingengine.com", TTL: 3600, Priority: priority(20)},
+		{Name: "", Type: "TXT", Content: "v=spf1 include:spf.messagingengine.com ?all", TTL: 3600},
+		{Name: "fm1._domainkey", Type: "CNAME", Content: "fm1.{{.domain}}.dkim.fmhosted.com", TTL: 3600},
+		{Name: "fm2._domainkey", Type: "CNAME", Content: "fm2.{{.domain}}.dkim.fmhosted.com", TTL: 3600},
+		{Name: "fm3._domainkey", Type: "CNAME", Content: "fm3.{{.domain}}.dkim.fmhosted.com", TTL: 3600},
+	},
+}
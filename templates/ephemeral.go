@@ -0,0 +1,112 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mydnshost "github.com/mydnshost/mydnshost-go-api"
+	"strings"
+)
+
+const ephemeralTTL = 300
+
+// Environment describes the standard set of records for a short-lived environment, such as a preview deployment
+// of a pull request.
+type Environment struct {
+	// Name is the environment's name relative to the domain, e.g. "pr-123.dev".
+	Name string
+	// Address is the IPv4 address the environment is served from.
+	Address string
+	// Owner identifies whoever created the environment, e.g. a CI pipeline. It is stored in a TXT record to label
+	// the environment's records as belonging to it.
+	Owner string
+	// TTL of the created records. Defaults to 5 minutes.
+	TTL int
+}
+
+// EnvironmentManifest records exactly which records were created for an environment, so that they can be removed
+// by TeardownEnvironment. It can be serialised to JSON to persist it between runs.
+type EnvironmentManifest struct {
+	Domain  string                     `json:"domain"`
+	Name    string                     `json:"name"`
+	Owner   string                     `json:"owner"`
+	Records []mydnshost.ExistingRecord `json:"records"`
+}
+
+// Records returns the environment's records within the given domain: an A record for the name, a wildcard CNAME
+// for names below it, and a TXT record at "_owner.<name>" labelling the owner.
+func (e Environment) Records(domain string) []mydnshost.Record {
+	ttl := e.TTL
+	if ttl == 0 {
+		ttl = ephemeralTTL
+	}
+
+	return []mydnshost.Record{
+		{Name: e.Name, Type: "A", Content: e.Address, TTL: ttl},
+		{Name: "*." + e.Name, Type: "CNAME", Content: fmt.Sprintf("%s.%s", e.Name, strings.TrimSuffix(domain, ".")), TTL: ttl},
+		{Name: "_owner." + e.Name, Type: "TXT", Content: ownerLabel(e.Owner), TTL: ttl},
+	}
+}
+
+// CreateEnvironment creates the environment's records, failing with a *ConflictError if any existing records
+// conflict with them. The returned manifest lists the records created.
+func CreateEnvironment(ctx context.Context, c mydnshost.ZoneProvider, domain string, env Environment) (*EnvironmentManifest, error) {
+	if env.Name == "" || env.Address == "" || env.Owner == "" {
+		return nil, errors.New("environment requires a name, address and owner")
+	}
+
+	res, err := Apply(ctx, c, domain, env.Records(domain), false)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &EnvironmentManifest{
+		Domain: domain,
+		Name:   env.Name,
+		Owner:  env.Owner,
+	}
+	for i := range res.Changed {
+		manifest.Records = append(manifest.Records, res.Changed[i].ExistingRecord)
+	}
+	return manifest, nil
+}
+
+// TeardownEnvironment deletes the records listed in the manifest. As a safeguard, it first checks that the
+// environment's owner label still names exactly the manifest's owner, and refuses to delete anything if it doesn't.
+// Records that have already been deleted are skipped.
+func TeardownEnvironment(ctx context.Context, c mydnshost.ZoneProvider, manifest *EnvironmentManifest) (*mydnshost.ModifyRecordsResponse, error) {
+	current, err := c.Records(ctx, manifest.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	label := mydnshost.Record{Name: "_owner." + manifest.Name, Type: "TXT", Content: ownerLabel(manifest.Owner)}
+	owned := false
+	existing := make(map[int]bool, len(current.Records))
+	for i := range current.Records {
+		existing[current.Records[i].Id] = true
+		if identical(current.Records[i].Record, label) {
+			owned = true
+		}
+	}
+
+	if !owned {
+		return nil, fmt.Errorf("environment %s is not labelled as owned by %s; refusing to delete it", manifest.Name, manifest.Owner)
+	}
+
+	var operations []mydnshost.RecordOperation
+	for i := range manifest.Records {
+		if existing[manifest.Records[i].Id] {
+			operations = append(operations, mydnshost.DeleteRecord(manifest.Records[i].Id))
+		}
+	}
+
+	if len(operations) == 0 {
+		return &mydnshost.ModifyRecordsResponse{Serial: current.Soa.Serial}, nil
+	}
+	return c.ModifyRecords(ctx, manifest.Domain, operations...)
+}
+
+func ownerLabel(owner string) string {
+	return "owner=" + owner
+}
@@ -0,0 +1,164 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	mydnshost "github.com/mydnshost/mydnshost-go-api"
+	"strings"
+)
+
+const hostingTTL = 3600
+
+// GitHubPagesSite returns the records needed to serve a GitHub Pages site at both the apex and www.
+func GitHubPagesSite(owner string) []mydnshost.Record {
+	return append(GitHubPages(owner, ""), GitHubPages(owner, "www")...)
+}
+
+// Netlify returns the records needed to serve the given Netlify site (its netlify.app subdomain, e.g. "example")
+// at both the apex and www.
+func Netlify(site string) []mydnshost.Record {
+	return []mydnshost.Record{
+		{Name: "", Type: "A", Content: "75.2.60.5", TTL: hostingTTL},
+		{Name: "www", Type: "CNAME", Content: fmt.Sprintf("%s.netlify.app", strings.ToLower(site)), TTL: hostingTTL},
+	}
+}
+
+// Vercel returns the records needed to serve a Vercel project at both the apex and www.
+func Vercel() []mydnshost.Record {
+	return []mydnshost.Record{
+		{Name: "", Type: "A", Content: "76.76.21.21", TTL: hostingTTL},
+		{Name: "www", Type: "CNAME", Content: "cname.vercel-dns.com", TTL: hostingTTL},
+	}
+}
+
+// Conflict describes an existing record that cannot coexist with a record being added.
+type Conflict struct {
+	Existing mydnshost.ExistingRecord
+	Proposed mydnshost.Record
+}
+
+// ConflictError is returned by Apply if existing records conflict with those being added.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	parts := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		parts[i] = fmt.Sprintf("existing %s record for %q (%s) conflicts with %s record %q",
+			c.Existing.Type, c.Existing.Name, c.Existing.Content, c.Proposed.Type, c.Proposed.Content)
+	}
+	return fmt.Sprintf("%d conflicting records: %s", len(e.Conflicts), strings.Join(parts, "; "))
+}
+
+// FindConflicts checks which existing records conflict with the proposed records. A record conflicts if it shares a
+// name with a proposed CNAME (or is itself a CNAME sharing a name with a proposed record), or if it has the same
+// name and type as a proposed A or AAAA record but different content. Existing records identical to a proposed
+// record are not conflicts.
+func FindConflicts(existing []mydnshost.ExistingRecord, proposed []mydnshost.Record) []Conflict {
+	var conflicts []Conflict
+	for i := range existing {
+		e := existing[i]
+		if proposes(proposed, e.Record) {
+			continue
+		}
+
+		for j := range proposed {
+			p := proposed[j]
+			if !strings.EqualFold(e.Name, p.Name) {
+				continue
+			}
+
+			cname := strings.EqualFold(e.Type, "CNAME") || strings.EqualFold(p.Type, "CNAME")
+			address := strings.EqualFold(e.Type, p.Type) && (strings.EqualFold(p.Type, "A") || strings.EqualFold(p.Type, "AAAA"))
+			if cname || address {
+				conflicts = append(conflicts, Conflict{Existing: e, Proposed: p})
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// Apply adds the given records to the domain in a single call, skipping any that already exist. If any existing
+// records conflict with those being added, Apply returns a *ConflictError and makes no changes unless replace is
+// true, in which case the conflicting records are deleted as part of the same call. If c is a *mydnshost.Client,
+// the changes are checked against its Guardrails before they are made.
+func Apply(ctx context.Context, c mydnshost.ZoneProvider, domain string, records []mydnshost.Record, replace bool) (*mydnshost.ModifyRecordsResponse, error) {
+	current, err := c.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := FindConflicts(current.Records, records)
+	if len(conflicts) > 0 && !replace {
+		return nil, &ConflictError{Conflicts: conflicts}
+	}
+
+	var operations []mydnshost.RecordOperation
+	deleted := make(map[int]bool, len(conflicts))
+	for i := range conflicts {
+		operations = append(operations, mydnshost.DeleteRecord(conflicts[i].Existing.Id))
+		deleted[conflicts[i].Existing.Id] = true
+	}
+
+	var remaining []mydnshost.ExistingRecord
+	for i := range current.Records {
+		if !deleted[current.Records[i].Id] {
+			remaining = append(remaining, current.Records[i])
+		}
+	}
+
+	for i := range records {
+		if !exists(remaining, records[i]) {
+			operations = append(operations, mydnshost.CreateRecord(records[i]))
+		}
+	}
+
+	if len(operations) == 0 {
+		return &mydnshost.ModifyRecordsResponse{Serial: current.Soa.Serial}, nil
+	}
+
+	return c.ModifyRecords(ctx, domain, operations...)
+}
+
+// identical checks if two records have the same name, type and content. Content is compared exactly, so that
+// TXT values such as verification tokens and owner labels must match, unless it is a hostname, which is compared
+// case-insensitively.
+func identical(a, b mydnshost.Record) bool {
+	if !strings.EqualFold(a.Name, b.Name) || !strings.EqualFold(a.Type, b.Type) {
+		return false
+	}
+	if hostnameContent(a.Type) {
+		return strings.EqualFold(a.Content, b.Content)
+	}
+	return a.Content == b.Content
+}
+
+// hostnameContent checks if the content of records of the given type is a hostname.
+func hostnameContent(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "CNAME", "MX", "NS", "PTR", "DNAME", "SRV":
+		return true
+	}
+	return false
+}
+
+// proposes reports whether record is identical to any of the proposed records.
+func proposes(proposed []mydnshost.Record, record mydnshost.Record) bool {
+	for i := range proposed {
+		if identical(proposed[i], record) {
+			return true
+		}
+	}
+	return false
+}
+
+func exists(existing []mydnshost.ExistingRecord, record mydnshost.Record) bool {
+	for i := range existing {
+		if identical(existing[i].Record, record) {
+			return true
+		}
+	}
+	return false
+}
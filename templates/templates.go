@@ -0,0 +1,121 @@
+// Package templates provides reusable, parameterised sets of DNS records that can be expanded into record operations
+// for use with mydnshost.Client.ModifyRecords.
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mydnshost "github.com/mydnshost/mydnshost-go-api"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Template is a named set of records. The Name, Type and Content of each record may refer to parameters using
+// text/template syntax, e.g. "{{.target}}". Every parameter referenced must be listed in Parameters.
+type Template struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Parameters  []string           `json:"parameters,omitempty"`
+	Records     []mydnshost.Record `json:"records"`
+}
+
+// Expand substitutes the given parameters into the template's records. All of the template's parameters must be
+// supplied with a non-empty value.
+func (t *Template) Expand(params map[string]string) ([]mydnshost.Record, error) {
+	for _, p := range t.Parameters {
+		if params[p] == "" {
+			return nil, fmt.Errorf("template %s: missing parameter %q", t.Name, p)
+		}
+	}
+
+	records := make([]mydnshost.Record, len(t.Records))
+	for i := range t.Records {
+		r := t.Records[i]
+
+		var err error
+		if r.Name, err = t.substitute(r.Name, params); err != nil {
+			return nil, err
+		}
+		if r.Type, err = t.substitute(r.Type, params); err != nil {
+			return nil, err
+		}
+		if r.Content, err = t.substitute(r.Content, params); err != nil {
+			return nil, err
+		}
+
+		records[i] = r
+	}
+	return records, nil
+}
+
+// Operations expands the template and returns operations that will create each of its records.
+func (t *Template) Operations(params map[string]string) ([]mydnshost.RecordOperation, error) {
+	records, err := t.Expand(params)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]mydnshost.RecordOperation, len(records))
+	for i := range records {
+		operations[i] = mydnshost.CreateRecord(records[i])
+	}
+	return operations, nil
+}
+
+func (t *Template) substitute(text string, params map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, params); err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// Load reads a JSON array of user-defined templates.
+func Load(r io.Reader) ([]*Template, error) {
+	var templates []*Template
+	if err := json.NewDecoder(r).Decode(&templates); err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		if templates[i].Name == "" {
+			return nil, fmt.Errorf("template %d has no name", i)
+		}
+	}
+	return templates, nil
+}
+
+// Builtin returns all of the templates provided by this package.
+func Builtin() []*Template {
+	return []*Template{
+		BasicWeb,
+		GoogleWorkspace,
+		Microsoft365,
+		Fastmail,
+	}
+}
+
+// Find returns the template with the given name from the list, or nil if there isn't one.
+func Find(templates []*Template, name string) *Template {
+	for i := range templates {
+		if templates[i].Name == name {
+			return templates[i]
+		}
+	}
+	return nil
+}
+
+func priority(p int) *int {
+	return &p
+}
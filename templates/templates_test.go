@@ -0,0 +1,288 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mydnshost "github.com/mydnshost/mydnshost-go-api"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTemplate_Expand(t *testing.T) {
+	records, err := Fastmail.Expand(map[string]string{"domain": "example.com"})
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+
+	if len(records) != len(Fastmail.Records) {
+		t.Fatalf("Expand() returned %d records, want %d", len(records), len(Fastmail.Records))
+	}
+	if got, want := records[3].Content, "fm1.example.com.dkim.fmhosted.com"; got != want {
+		t.Errorf("Expand() content = %q, want %q", got, want)
+	}
+	if *records[0].Priority != 10 {
+		t.Errorf("Expand() priority = %d, want 10", *records[0].Priority)
+	}
+	if Fastmail.Records[3].Content != "fm1.{{.domain}}.dkim.fmhosted.com" {
+		t.Errorf("Expand() modified the template")
+	}
+}
+
+func TestTemplate_ExpandMissingParameter(t *testing.T) {
+	if _, err := BasicWeb.Expand(nil); err == nil {
+		t.Errorf("Expand() with no parameters should fail")
+	}
+
+	undeclared := &Template{Name: "undeclared", Records: BasicWeb.Records}
+	if _, err := undeclared.Expand(nil); err == nil {
+		t.Errorf("Expand() with an undeclared parameter should fail")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	input := `[{
+		"name": "mail-relay",
+		"parameters": ["relay"],
+		"records": [{"name": "", "type": "MX", "content": "{{.relay}}", "ttl": 300, "priority": 5}]
+	}]`
+
+	templates, err := Load(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tmpl := Find(templates, "mail-relay")
+	if tmpl == nil {
+		t.Fatalf("Find() didn't find loaded template")
+	}
+
+	operations, err := tmpl.Operations(map[string]string{"relay": "relay.example.net"})
+	if err != nil {
+		t.Fatalf("Operations() error = %v", err)
+	}
+	if got, want := string(operations[0]), `{"type":"MX","content":"relay.example.net","ttl":300,"priority":5}`; got != want {
+		t.Errorf("Operations() = %s, want %s", got, want)
+	}
+}
+
+func TestVerificationRecords(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"google", GoogleSiteVerification("abc123").Content, "google-site-verification=abc123"},
+		{"google with prefix", GoogleSiteVerification("google-site-verification=abc123").Content, "google-site-verification=abc123"},
+		{"microsoft", MicrosoftVerification("ms12345678").Content, "MS=ms12345678"},
+		{"atlassian", AtlassianVerification(" token ").Content, "atlassian-domain-verification=token"},
+		{"github", GitHubPagesVerification("Octocat", "t0k3n").Name, "_github-pages-challenge-octocat"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	existing := []mydnshost.ExistingRecord{
+		{Id: 1, Record: mydnshost.Record{Name: "", Type: "A", Content: "192.0.2.1"}},
+		{Id: 2, Record: mydnshost.Record{Name: "", Type: "MX", Content: "mail.example.com"}},
+		{Id: 3, Record: mydnshost.Record{Name: "www", Type: "A", Content: "192.0.2.1"}},
+		{Id: 4, Record: mydnshost.Record{Name: "", Type: "A", Content: "75.2.60.5"}},
+	}
+
+	conflicts := FindConflicts(existing, Netlify("example"))
+	if len(conflicts) != 2 {
+		t.Fatalf("FindConflicts() found %d conflicts, want 2: %v", len(conflicts), conflicts)
+	}
+	if conflicts[0].Existing.Id != 1 || conflicts[1].Existing.Id != 3 {
+		t.Errorf("FindConflicts() = %v, want records 1 and 3", conflicts)
+	}
+}
+
+// staticZone is a ZoneProvider that serves a fixed set of records and records the operations it is given.
+type staticZone struct {
+	records    []mydnshost.ExistingRecord
+	operations []mydnshost.RecordOperation
+}
+
+func (z *staticZone) Records(ctx context.Context, domain string) (*mydnshost.RecordsResponse, error) {
+	return &mydnshost.RecordsResponse{Records: z.records}, nil
+}
+
+func (z *staticZone) ModifyRecords(ctx context.Context, domain string, operations ...mydnshost.RecordOperation) (*mydnshost.ModifyRecordsResponse, error) {
+	z.operations = append(z.operations, operations...)
+	return &mydnshost.ModifyRecordsResponse{}, nil
+}
+
+func existingRecords(records []mydnshost.Record) []mydnshost.ExistingRecord {
+	existing := make([]mydnshost.ExistingRecord, len(records))
+	for i := range records {
+		existing[i] = mydnshost.ExistingRecord{Id: i + 1, Record: records[i]}
+	}
+	return existing
+}
+
+func TestApply_AlreadyApplied(t *testing.T) {
+	records := GitHubPagesSite("example")
+	zone := &staticZone{records: existingRecords(records)}
+
+	if conflicts := FindConflicts(zone.records, records); len(conflicts) != 0 {
+		t.Errorf("FindConflicts() found %d conflicts with an identical zone, want 0: %v", len(conflicts), conflicts)
+	}
+
+	zone.records = append(zone.records, mydnshost.ExistingRecord{Id: 100, Record: mydnshost.Record{Name: "", Type: "A", Content: "192.0.2.1"}})
+	if _, err := Apply(context.Background(), zone, "example.com", records, true); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if len(zone.operations) != 1 || string(zone.operations[0]) != `{"id":100,"delete":true}` {
+		t.Errorf("Apply() operations = %q, want only the stale A record deleted", zone.operations)
+	}
+}
+
+func TestApply_Guardrails(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"response":{"records":[
+				{"id":"1","name":"","type":"A","content":"192.0.2.1","ttl":"3600"},
+				{"id":"2","name":"","type":"A","content":"192.0.2.2","ttl":"3600"}
+			],"soa":{"serial":"1"}}}`))
+		} else {
+			posts++
+			_, _ = w.Write([]byte(`{"response":{"serial":"2","changed":[]}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := &mydnshost.Client{BaseURL: server.URL + "/1.0/", Guardrails: &mydnshost.Guardrails{MaxDeletePercent: 50}}
+
+	if _, err := Apply(context.Background(), c, "example.com", Vercel(), true); !errors.Is(err, mydnshost.ErrGuardrailExceeded) {
+		t.Fatalf("Apply() replacing every record error = %v, want ErrGuardrailExceeded", err)
+	}
+	if posts != 0 {
+		t.Errorf("Apply() made %d changes despite exceeding the guardrails", posts)
+	}
+}
+
+func TestTeardownEnvironment(t *testing.T) {
+	env := Environment{Name: "pr-1.dev", Address: "192.0.2.10", Owner: "ci"}
+	zone := &staticZone{records: []mydnshost.ExistingRecord{
+		{Id: 1, Record: mydnshost.Record{Name: "", Type: "A", Content: "192.0.2.1"}},
+		{Id: 2, Record: mydnshost.Record{Name: "dev", Type: "A", Content: "192.0.2.2"}},
+		// Added to the environment by hand after it was created.
+		{Id: 13, Record: mydnshost.Record{Name: "pr-1.dev", Type: "TXT", Content: "keep me"}},
+	}}
+
+	manifest := &EnvironmentManifest{Domain: "example.com", Name: env.Name, Owner: env.Owner}
+	for i, record := range env.Records("example.com") {
+		existing := mydnshost.ExistingRecord{Id: 10 + i, Record: record}
+		manifest.Records = append(manifest.Records, existing)
+		zone.records = append(zone.records, existing)
+	}
+
+	if _, err := TeardownEnvironment(context.Background(), zone, manifest); err != nil {
+		t.Fatalf("TeardownEnvironment() error = %v", err)
+	}
+
+	want := []string{`{"id":10,"delete":true}`, `{"id":11,"delete":true}`, `{"id":12,"delete":true}`}
+	if len(zone.operations) != len(want) {
+		t.Fatalf("TeardownEnvironment() operations = %q, want %q", zone.operations, want)
+	}
+	for i := range want {
+		if string(zone.operations[i]) != want[i] {
+			t.Errorf("TeardownEnvironment() operations = %q, want %q", zone.operations, want)
+			break
+		}
+	}
+}
+
+func TestTeardownEnvironment_NotOwned(t *testing.T) {
+	for _, label := range []string{"owner=someone-else", "owner=CI", "owner=ci "} {
+		t.Run(label, func(t *testing.T) {
+			zone := &staticZone{records: []mydnshost.ExistingRecord{
+				{Id: 10, Record: mydnshost.Record{Name: "pr-1.dev", Type: "A", Content: "192.0.2.10"}},
+				{Id: 12, Record: mydnshost.Record{Name: "_owner.pr-1.dev", Type: "TXT", Content: label}},
+			}}
+			manifest := &EnvironmentManifest{Domain: "example.com", Name: "pr-1.dev", Owner: "ci", Records: zone.records}
+
+			if _, err := TeardownEnvironment(context.Background(), zone, manifest); err == nil {
+				t.Errorf("TeardownEnvironment() of an environment labelled %q should fail", label)
+			}
+			if len(zone.operations) != 0 {
+				t.Errorf("TeardownEnvironment() sent %d operations for an environment it doesn't own", len(zone.operations))
+			}
+		})
+	}
+}
+
+func TestIdentical(t *testing.T) {
+	tests := []struct {
+		a, b mydnshost.Record
+		want bool
+	}{
+		{mydnshost.Record{Name: "WWW", Type: "cname", Content: "Example.netlify.app"}, mydnshost.Record{Name: "www", Type: "CNAME", Content: "example.netlify.app"}, true},
+		{mydnshost.Record{Name: "", Type: "MX", Content: "MX1.example.com"}, mydnshost.Record{Name: "", Type: "MX", Content: "mx1.example.com"}, true},
+		{mydnshost.Record{Name: "", Type: "TXT", Content: "google-site-verification=AbC"}, mydnshost.Record{Name: "", Type: "TXT", Content: "google-site-verification=abc"}, false},
+		{mydnshost.Record{Name: "", Type: "TXT", Content: "v=spf1 -all"}, mydnshost.Record{Name: "", Type: "TXT", Content: "v=spf1 -all"}, true},
+		{mydnshost.Record{Name: "", Type: "A", Content: "192.0.2.1"}, mydnshost.Record{Name: "", Type: "AAAA", Content: "192.0.2.1"}, false},
+	}
+	for _, tt := range tests {
+		if got := identical(tt.a, tt.b); got != tt.want {
+			t.Errorf("identical(%+v, %+v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCreateEnvironment(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, strings.TrimSpace(string(body))))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"response":{"records":[{"id":"1","name":"","type":"A","content":"192.0.2.1","ttl":"3600"}],"soa":{"serial":"1"}}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"response":{"serial":"2","changed":[
+				{"id":"10","name":"pr-1.dev","type":"A","content":"192.0.2.10","ttl":"300"},
+				{"id":"11","name":"*.pr-1.dev","type":"CNAME","content":"pr-1.dev.example.com","ttl":"300"},
+				{"id":"12","name":"_owner.pr-1.dev","type":"TXT","content":"owner=ci","ttl":"300"}
+			]}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := &mydnshost.Client{BaseURL: server.URL + "/1.0/"}
+
+	manifest, err := CreateEnvironment(context.Background(), c, "example.com", Environment{Name: "pr-1.dev", Address: "192.0.2.10", Owner: "ci"})
+	if err != nil {
+		t.Fatalf("CreateEnvironment() error = %v", err)
+	}
+
+	want := []string{
+		"GET /1.0/domains/example.com/records ",
+		`POST /1.0/domains/example.com/records {"data":{"records":[` +
+			`{"name":"pr-1.dev","type":"A","content":"192.0.2.10","ttl":300},` +
+			`{"name":"*.pr-1.dev","type":"CNAME","content":"pr-1.dev.example.com","ttl":300},` +
+			`{"name":"_owner.pr-1.dev","type":"TXT","content":"owner=ci","ttl":300}]}}`,
+	}
+	if len(requests) != len(want) || requests[0] != want[0] || requests[1] != want[1] {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+
+	if manifest.Domain != "example.com" || manifest.Name != "pr-1.dev" || manifest.Owner != "ci" || len(manifest.Records) != 3 {
+		t.Fatalf("CreateEnvironment() manifest = %+v, want the three created records", manifest)
+	}
+	for i, id := range []int{10, 11, 12} {
+		if manifest.Records[i].Id != id {
+			t.Errorf("manifest record %d has id %d, want %d", i, manifest.Records[i].Id, id)
+		}
+	}
+}
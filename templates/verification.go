@@ -0,0 +1,77 @@
+package templates
+
+import (
+	"fmt"
+	mydnshost "github.com/mydnshost/mydnshost-go-api"
+	"strings"
+)
+
+const verificationTTL = 3600
+
+// GitHub Pages' documented apex addresses.
+var (
+	gitHubPagesIPv4 = []string{"185.199.108.153", "185.199.109.153", "185.199.110.153", "185.199.111.153"}
+	gitHubPagesIPv6 = []string{"2606:50c0:8000::153", "2606:50c0:8001::153", "2606:50c0:8002::153", "2606:50c0:8003::153"}
+)
+
+// verificationTXT creates a TXT record at the apex containing the token with the given prefix. If the token was
+// copied including its prefix, it isn't duplicated.
+func verificationTXT(prefix, token string) mydnshost.Record {
+	return mydnshost.Record{
+		Name:    "",
+		Type:    "TXT",
+		Content: prefix + strings.TrimPrefix(strings.TrimSpace(token), prefix),
+		TTL:     verificationTTL,
+	}
+}
+
+// GoogleSiteVerification creates the apex TXT record used by Google Search Console and Google Workspace to verify
+// domain ownership.
+func GoogleSiteVerification(token string) mydnshost.Record {
+	return verificationTXT("google-site-verification=", token)
+}
+
+// MicrosoftVerification creates the apex TXT record used by Microsoft 365 to verify domain ownership. The token is
+// the value shown in the admin centre, e.g. "ms12345678".
+func MicrosoftVerification(token string) mydnshost.Record {
+	return verificationTXT("MS=", token)
+}
+
+// AtlassianVerification creates the apex TXT record used by Atlassian to verify domain ownership.
+func AtlassianVerification(token string) mydnshost.Record {
+	return verificationTXT("atlassian-domain-verification=", token)
+}
+
+// GitHubPagesVerification creates the TXT record used by GitHub to verify a domain for the given user or
+// organisation's Pages sites.
+func GitHubPagesVerification(owner, token string) mydnshost.Record {
+	return mydnshost.Record{
+		Name:    fmt.Sprintf("_github-pages-challenge-%s", strings.ToLower(owner)),
+		Type:    "TXT",
+		Content: strings.TrimSpace(token),
+		TTL:     verificationTTL,
+	}
+}
+
+// GitHubPages creates the records needed to serve a GitHub Pages site for the given user or organisation. If name is
+// blank, the apex A and AAAA records are returned; otherwise a CNAME pointing the name at the owner's github.io
+// host is returned.
+func GitHubPages(owner, name string) []mydnshost.Record {
+	if name != "" {
+		return []mydnshost.Record{{
+			Name:    name,
+			Type:    "CNAME",
+			Content: fmt.Sprintf("%s.github.io", strings.ToLower(owner)),
+			TTL:     verificationTTL,
+		}}
+	}
+
+	records := make([]mydnshost.Record, 0, len(gitHubPagesIPv4)+len(gitHubPagesIPv6))
+	for _, ip := range gitHubPagesIPv4 {
+		records = append(records, mydnshost.Record{Name: "", Type: "A", Content: ip, TTL: verificationTTL})
+	}
+	for _, ip := range gitHubPagesIPv6 {
+		records = append(records, mydnshost.Record{Name: "", Type: "AAAA", Content: ip, TTL: verificationTTL})
+	}
+	return records
+}
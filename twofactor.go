@@ -0,0 +1,95 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// TwoFactorTOTP is the device type for RFC 6238 time-based one-time passwords.
+const TwoFactorTOTP = "rfc6238"
+
+// TwoFactorDevice is a two-factor authentication device registered to the current user.
+type TwoFactorDevice struct {
+	Id          int    `json:"id"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Created     int    `json:"created"`
+	LastUsed    int    `json:"lastused"`
+	Active      bool   `json:"active"`
+	// Key is the shared secret for the device. It is only populated when the device is created.
+	Key string `json:"key,omitempty"`
+}
+
+// TwoFactorDevices lists the two-factor authentication devices registered to the current user.
+func (c *Client) TwoFactorDevices(ctx context.Context) ([]TwoFactorDevice, error) {
+	res, err := c.request(ctx, http.MethodGet, "users/self/2fa", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if emptyList(*res.Response) {
+		return []TwoFactorDevice{}, nil
+	}
+
+	// The API returns devices keyed by their ID.
+	byId := make(map[string]wireTwoFactorDevice)
+	if err := c.codec().Unmarshal(*res.Response, &byId); err != nil {
+		return nil, err
+	}
+
+	devices := make([]TwoFactorDevice, 0, len(byId))
+	for id := range byId {
+		wire := byId[id]
+		devices = append(devices, wire.device())
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Id < devices[j].Id
+	})
+	return devices, nil
+}
+
+// CreateTwoFactorDevice registers a new TOTP device with the given description. The returned device includes the
+// shared secret, which should be loaded into an authenticator app. The device must be activated by calling
+// VerifyTwoFactorDevice with a code it has generated before it can be used to log in.
+func (c *Client) CreateTwoFactorDevice(ctx context.Context, description string) (*TwoFactorDevice, error) {
+	res, err := c.request(ctx, http.MethodPost, "users/self/2fa", apiRequest{
+		Data: struct {
+			Description string `json:"description"`
+			Type        string `json:"type"`
+		}{
+			Description: description,
+			Type:        TwoFactorTOTP,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireTwoFactorDevice{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+
+	device := wire.device()
+	return &device, nil
+}
+
+// VerifyTwoFactorDevice activates a newly created device by checking a code it has generated.
+func (c *Client) VerifyTwoFactorDevice(ctx context.Context, id int, code string) error {
+	_, err := c.request(ctx, http.MethodPost, fmt.Sprintf("users/self/2fa/%d/verify", id), apiRequest{
+		Data: struct {
+			Code string `json:"code"`
+		}{
+			Code: code,
+		},
+	})
+	return err
+}
+
+// DeleteTwoFactorDevice removes a two-factor authentication device from the current user.
+func (c *Client) DeleteTwoFactorDevice(ctx context.Context, id int) error {
+	_, err := c.request(ctx, http.MethodDelete, fmt.Sprintf("users/self/2fa/%d", id), nil)
+	return err
+}
@@ -0,0 +1,488 @@
+package mydnshost_go_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The types in this file describe the wire format of API 1.0 responses. They decode numeric and boolean fields
+// leniently, accepting values encoded either natively or as strings, and are converted into the exported response
+// types. This keeps the exported types stable if the API changes how it encodes a field.
+//
+// Client methods decode responses into these types through the client's Codec, so that the Codec handles the whole
+// response. The exported types' UnmarshalJSON methods use the same wire types, for callers decoding responses
+// themselves with encoding/json.
+
+// flexUint64 decodes a JSON number, or a string containing one.
+type flexUint64 uint64
+
+func (f *flexUint64) UnmarshalJSON(data []byte) error {
+	s, ok := unquote(data)
+	if !ok {
+		return nil
+	}
+
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid unsigned integer %s: %w", data, err)
+	}
+	*f = flexUint64(v)
+	return nil
+}
+
+// flexInt decodes a JSON number, or a string containing one.
+type flexInt int
+
+func (f *flexInt) UnmarshalJSON(data []byte) error {
+	s, ok := unquote(data)
+	if !ok {
+		return nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s: %w", data, err)
+	}
+	*f = flexInt(v)
+	return nil
+}
+
+// flexBool decodes a JSON boolean, a number (where non-zero is true), or a string containing either.
+type flexBool bool
+
+func (f *flexBool) UnmarshalJSON(data []byte) error {
+	s, ok := unquote(data)
+	if !ok {
+		return nil
+	}
+
+	if v, err := strconv.ParseBool(s); err == nil {
+		*f = flexBool(v)
+		return nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	*f = v != 0
+	return nil
+}
+
+// unquote returns the contents of a scalar JSON value with any surrounding quotes removed, or false if the value
+// is null or an empty string.
+func unquote(data []byte) (string, bool) {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		return "", false
+	}
+
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", false
+		}
+		s = strings.TrimSpace(s)
+	}
+	return s, s != ""
+}
+
+// emptyList reports whether data is an empty JSON array. The API returns collections keyed by ID as objects, but
+// PHP encodes an empty collection as [] rather than {}.
+func emptyList(data []byte) bool {
+	return strings.Join(strings.Fields(string(data)), "") == "[]"
+}
+
+func (f *flexInt) intPtr() *int {
+	if f == nil {
+		return nil
+	}
+	i := int(*f)
+	return &i
+}
+
+func (f *flexBool) boolPtr() *bool {
+	if f == nil {
+		return nil
+	}
+	b := bool(*f)
+	return &b
+}
+
+type wireRecord struct {
+	Id        flexInt   `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Content   string    `json:"content"`
+	TTL       flexInt   `json:"ttl"`
+	Priority  *flexInt  `json:"priority"`
+	Disabled  *flexBool `json:"disabled"`
+	ChangedAt flexInt   `json:"changed_at"`
+	ChangedBy *flexInt  `json:"changed_by"`
+	Updated   flexBool  `json:"updated"`
+	Deleted   flexBool  `json:"deleted"`
+}
+
+func (w *wireRecord) existingRecord() ExistingRecord {
+	return ExistingRecord{
+		Record: Record{
+			Name:     w.Name,
+			Type:     w.Type,
+			Content:  w.Content,
+			TTL:      int(w.TTL),
+			Priority: w.Priority.intPtr(),
+			Disabled: w.Disabled.boolPtr(),
+		},
+		Id:        int(w.Id),
+		ChangedAt: int(w.ChangedAt),
+		ChangedBy: w.ChangedBy.intPtr(),
+	}
+}
+
+func existingRecords(wire []wireRecord) []ExistingRecord {
+	if wire == nil {
+		return nil
+	}
+
+	records := make([]ExistingRecord, len(wire))
+	for i := range wire {
+		records[i] = wire[i].existingRecord()
+	}
+	return records
+}
+
+type wireSOA struct {
+	PrimaryNS    string     `json:"primaryNS"`
+	AdminAddress string     `json:"adminAddress"`
+	Serial       flexUint64 `json:"serial"`
+	Refresh      flexUint64 `json:"refresh"`
+	Retry        flexUint64 `json:"retry"`
+	Expire       flexUint64 `json:"expire"`
+	MinTTL       flexUint64 `json:"min_ttl"`
+}
+
+func (w *wireSOA) soa() SOA {
+	return SOA{
+		PrimaryNS:    w.PrimaryNS,
+		AdminAddress: w.AdminAddress,
+		Serial:       uint64(w.Serial),
+		Refresh:      uint64(w.Refresh),
+		Retry:        uint64(w.Retry),
+		Expire:       uint64(w.Expire),
+		MinTTL:       uint64(w.MinTTL),
+	}
+}
+
+type wireRecordsResponse struct {
+	Records []wireRecord `json:"records"`
+	HasNS   flexBool     `json:"hasNS"`
+	Soa     wireSOA      `json:"soa"`
+}
+
+func (w *wireRecordsResponse) response() *RecordsResponse {
+	return &RecordsResponse{
+		Records: existingRecords(w.Records),
+		HasNS:   bool(w.HasNS),
+		Soa:     w.Soa.soa(),
+	}
+}
+
+func (r *RecordsResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireRecordsResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireModifyRecordsResponse struct {
+	Serial  flexUint64   `json:"serial"`
+	Changed []wireRecord `json:"changed"`
+}
+
+func (w *wireModifyRecordsResponse) response() *ModifyRecordsResponse {
+	r := &ModifyRecordsResponse{Serial: uint64(w.Serial)}
+	if w.Changed != nil {
+		r.Changed = make([]ChangedRecord, len(w.Changed))
+		for i := range w.Changed {
+			r.Changed[i] = ChangedRecord{
+				ExistingRecord: w.Changed[i].existingRecord(),
+				Updated:        bool(w.Changed[i].Updated),
+				Deleted:        bool(w.Changed[i].Deleted),
+			}
+		}
+	}
+	return r
+}
+
+func (r *ModifyRecordsResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireModifyRecordsResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireFindRecordsResponse struct {
+	Records []wireRecord `json:"records"`
+}
+
+func (w *wireFindRecordsResponse) response() *FindRecordsResponse {
+	return &FindRecordsResponse{Records: existingRecords(w.Records)}
+}
+
+func (r *FindRecordsResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireFindRecordsResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireDeletedNamedRecordsResponse struct {
+	Deleted flexInt    `json:"deleted"`
+	Serial  flexUint64 `json:"serial"`
+}
+
+func (w *wireDeletedNamedRecordsResponse) response() *DeletedNamedRecordsResponse {
+	return &DeletedNamedRecordsResponse{Deleted: int(w.Deleted), Serial: uint64(w.Serial)}
+}
+
+func (r *DeletedNamedRecordsResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireDeletedNamedRecordsResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireDNSSEC struct {
+	DS     []string `json:"ds"`
+	DNSKEY []string `json:"dnskey"`
+}
+
+type wireDomainDetails struct {
+	Id                    flexInt                `json:"id"`
+	Domain                string                 `json:"domain"`
+	Disabled              flexBool               `json:"disabled"`
+	DefaultTTL            flexInt                `json:"defaultttl"`
+	AliasOf               *string                `json:"aliasof"`
+	Access                map[string]AccessLevel `json:"access"`
+	VerificationState     *string                `json:"verificationstate"`
+	VerificationStateTime flexInt                `json:"verificationstatetime"`
+	DNSSECEnabled         flexBool               `json:"dnssec"`
+	DNSSEC                *wireDNSSEC            `json:"DNSSEC"`
+}
+
+func (w *wireDomainDetails) details() *DomainDetails {
+	d := &DomainDetails{
+		Id:                    int(w.Id),
+		Domain:                w.Domain,
+		Disabled:              bool(w.Disabled),
+		DefaultTTL:            int(w.DefaultTTL),
+		Access:                w.Access,
+		VerificationStateTime: int(w.VerificationStateTime),
+		DNSSECEnabled:         bool(w.DNSSECEnabled),
+	}
+	if w.AliasOf != nil {
+		d.AliasOf = *w.AliasOf
+	}
+	if w.VerificationState != nil {
+		d.VerificationState = *w.VerificationState
+	}
+	if w.DNSSEC != nil {
+		d.DNSSEC = w.DNSSEC.info()
+	}
+	return d
+}
+
+func (d *DomainDetails) UnmarshalJSON(data []byte) error {
+	wire := &wireDomainDetails{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*d = *wire.details()
+	return nil
+}
+
+type wireDomainLogEntry struct {
+	Time    flexInt     `json:"time"`
+	User    *string     `json:"user"`
+	Action  string      `json:"action"`
+	Message *string     `json:"message"`
+	Record  *wireRecord `json:"record"`
+}
+
+func (w *wireDomainLogEntry) entry() DomainLogEntry {
+	e := DomainLogEntry{
+		Time:   int(w.Time),
+		Action: w.Action,
+	}
+	if w.User != nil {
+		e.User = *w.User
+	}
+	if w.Message != nil {
+		e.Message = *w.Message
+	}
+	if w.Record != nil {
+		record := w.Record.existingRecord()
+		e.Record = &record
+	}
+	return e
+}
+
+func (e *DomainLogEntry) UnmarshalJSON(data []byte) error {
+	wire := &wireDomainLogEntry{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*e = wire.entry()
+	return nil
+}
+
+type wireImportZoneResponse struct {
+	Serial flexUint64 `json:"serial"`
+}
+
+func (w *wireImportZoneResponse) response() *ImportZoneResponse {
+	return &ImportZoneResponse{Serial: uint64(w.Serial)}
+}
+
+func (r *ImportZoneResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireImportZoneResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireUpdateRecordResponse struct {
+	wireRecord
+	Serial flexUint64 `json:"serial"`
+}
+
+func (w *wireUpdateRecordResponse) response() *UpdateRecordResponse {
+	return &UpdateRecordResponse{ExistingRecord: w.existingRecord(), Serial: uint64(w.Serial)}
+}
+
+func (r *UpdateRecordResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireUpdateRecordResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireDeleteRecordResponse struct {
+	Deleted flexBool   `json:"deleted"`
+	Serial  flexUint64 `json:"serial"`
+}
+
+func (w *wireDeleteRecordResponse) response() *DeleteRecordResponse {
+	return &DeleteRecordResponse{Deleted: bool(w.Deleted), Serial: uint64(w.Serial)}
+}
+
+func (r *DeleteRecordResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireDeleteRecordResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireDeleteDomainResponse struct {
+	Deleted flexBool `json:"deleted"`
+}
+
+func (w *wireDeleteDomainResponse) response() *DeleteDomainResponse {
+	return &DeleteDomainResponse{Deleted: bool(w.Deleted)}
+}
+
+func (r *DeleteDomainResponse) UnmarshalJSON(data []byte) error {
+	wire := &wireDeleteDomainResponse{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*r = *wire.response()
+	return nil
+}
+
+type wireTwoFactorDevice struct {
+	Id          flexInt  `json:"id"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Created     flexInt  `json:"created"`
+	LastUsed    flexInt  `json:"lastused"`
+	Active      flexBool `json:"active"`
+	Key         string   `json:"key"`
+}
+
+func (w *wireTwoFactorDevice) device() TwoFactorDevice {
+	return TwoFactorDevice{
+		Id:          int(w.Id),
+		Description: w.Description,
+		Type:        w.Type,
+		Created:     int(w.Created),
+		LastUsed:    int(w.LastUsed),
+		Active:      bool(w.Active),
+		Key:         w.Key,
+	}
+}
+
+func (d *TwoFactorDevice) UnmarshalJSON(data []byte) error {
+	wire := &wireTwoFactorDevice{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*d = wire.device()
+	return nil
+}
+
+type wireDomainHook struct {
+	Id       flexInt  `json:"id"`
+	URL      string   `json:"url"`
+	Password string   `json:"password"`
+	Disabled flexBool `json:"disabled"`
+	Created  flexInt  `json:"created"`
+	LastUsed flexInt  `json:"lastused"`
+}
+
+func (w *wireDomainHook) hook() DomainHook {
+	return DomainHook{
+		Id:       int(w.Id),
+		URL:      w.URL,
+		Password: w.Password,
+		Disabled: bool(w.Disabled),
+		Created:  int(w.Created),
+		LastUsed: int(w.LastUsed),
+	}
+}
+
+func (h *DomainHook) UnmarshalJSON(data []byte) error {
+	wire := &wireDomainHook{}
+	if err := json.Unmarshal(data, wire); err != nil {
+		return err
+	}
+
+	*h = wire.hook()
+	return nil
+}
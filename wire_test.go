@@ -0,0 +1,36 @@
+package mydnshost_go_api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLenientDecoding(t *testing.T) {
+	input := `{
+		"records": [{"id": "10", "name": "", "type": "MX", "content": "mail", "ttl": "3600", "priority": "10", "disabled": "0", "changed_at": "1599999000", "changed_by": null}],
+		"hasNS": 1,
+		"soa": {"serial": "2020091301", "refresh": 86400, "retry": "7200", "expire": "", "min_ttl": null}
+	}`
+
+	response := &RecordsResponse{}
+	if err := json.Unmarshal([]byte(input), response); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	r := response.Records[0]
+	if r.Id != 10 || r.TTL != 3600 || *r.Priority != 10 || *r.Disabled || r.ChangedAt != 1599999000 || r.ChangedBy != nil {
+		t.Errorf("record = %+v", r)
+	}
+	if !response.HasNS || response.Soa.Serial != 2020091301 || response.Soa.Refresh != 86400 || response.Soa.Retry != 7200 {
+		t.Errorf("response = %+v", response)
+	}
+	if response.Soa.Expire != 0 || response.Soa.MinTTL != 0 {
+		t.Errorf("blank fields decoded as %d, %d", response.Soa.Expire, response.Soa.MinTTL)
+	}
+}
+
+func TestLenientDecodingInvalid(t *testing.T) {
+	if err := json.Unmarshal([]byte(`{"serial": "twelve"}`), &ModifyRecordsResponse{}); err == nil {
+		t.Errorf("Unmarshal() of invalid serial should fail")
+	}
+}
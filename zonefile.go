@@ -0,0 +1,139 @@
+package mydnshost_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ExportZone retrieves a domain's records as a BIND-format zone file.
+func (c *Client) ExportZone(ctx context.Context, domain string) (string, error) {
+	res, err := c.request(ctx, http.MethodGet, fmt.Sprintf("domains/%s/export", domain), nil)
+	if err != nil {
+		return "", err
+	}
+
+	response := struct {
+		Zone string `json:"zone"`
+	}{}
+	if err := c.codec().Unmarshal(*res.Response, &response); err != nil {
+		return "", err
+	}
+	return response.Zone, nil
+}
+
+// ImportZoneResponse describes the result of importing a zone file.
+type ImportZoneResponse struct {
+	Serial uint64 `json:"serial"`
+}
+
+// ImportZoneOpts configures how ImportZone loads a zone file.
+type ImportZoneOpts struct {
+	// Merge adds the zone file's records to the domain's existing records, rather than replacing them. The API's
+	// import endpoint only replaces zones, so merges are made by parsing the zone file with ParseZone and
+	// creating the records that are missing from the domain with ModifyRecords. The TTLs of records that exist in
+	// both are updated where the zone file gives one. No records are deleted.
+	Merge bool
+}
+
+// ImportZone loads a BIND-format zone file into a domain. Unless opts.Merge is set, the zone file replaces all of
+// the domain's existing records. If the client has Guardrails, the zone file is parsed with ParseZone and compared
+// with the domain's records, and the import is refused if replacing them would exceed the guardrails.
+func (c *Client) ImportZone(ctx context.Context, domain, zone string, opts ImportZoneOpts) (*ImportZoneResponse, error) {
+	if err := c.checkFrozen(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	if opts.Merge {
+		return c.mergeZone(ctx, domain, zone)
+	}
+	if err := c.checkImportGuardrails(ctx, domain, zone); err != nil {
+		return nil, err
+	}
+
+	res, err := c.request(ctx, http.MethodPost, fmt.Sprintf("domains/%s/import", domain), apiRequest{
+		Data: struct {
+			Zone string `json:"zone"`
+		}{
+			Zone: zone,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wire := &wireImportZoneResponse{}
+	if err := c.codec().Unmarshal(*res.Response, wire); err != nil {
+		return nil, err
+	}
+	response := wire.response()
+
+	c.zoneChanged(domain, response.Serial)
+	return response, nil
+}
+
+// checkImportGuardrails returns an error wrapping ErrGuardrailExceeded if replacing the domain's records with those
+// in the zone file would exceed the client's guardrails.
+func (c *Client) checkImportGuardrails(ctx context.Context, domain, zone string) error {
+	guardrails := c.guardrails(ctx)
+	if guardrails == nil {
+		return nil
+	}
+
+	records, err := ParseZone(zone, domain)
+	if err != nil {
+		return fmt.Errorf("unable to check zone file against guardrails: %w", err)
+	}
+
+	current, err := c.Records(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	diff := diffRecords(domain, asExistingRecords(records), current.Records)
+	return guardrails.check(domain, len(diff.Operations()), len(diff.Extra), len(current.Records))
+}
+
+// mergeZone adds the records in a zone file to a domain, without removing any of its existing records.
+func (c *Client) mergeZone(ctx context.Context, domain, zone string) (*ImportZoneResponse, error) {
+	records, err := ParseZone(zone, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.Records(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffRecords(domain, asExistingRecords(records), current.Records)
+
+	var operations []RecordOperation
+	for i := range diff.Changed {
+		if ttl := diff.Changed[i].Source.TTL; ttl != 0 && ttl != diff.Changed[i].Target.TTL {
+			operations = append(operations, ModifyRecord(diff.Changed[i].Target.Id, Record{TTL: ttl}))
+		}
+	}
+	for i := range diff.Missing {
+		operations = append(operations, CreateRecord(diff.Missing[i].Record))
+	}
+
+	if len(operations) == 0 {
+		return &ImportZoneResponse{Serial: current.Soa.Serial}, nil
+	}
+
+	res, err := c.ModifyRecords(ctx, domain, operations...)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportZoneResponse{Serial: res.Serial}, nil
+}
+
+// asExistingRecords wraps records from a zone file as ExistingRecords, so that they can be compared with a domain's records.
+func asExistingRecords(records []Record) []ExistingRecord {
+	wrapped := make([]ExistingRecord, len(records))
+	for i := range records {
+		wrapped[i] = ExistingRecord{Record: records[i]}
+	}
+	return wrapped
+}
@@ -0,0 +1,266 @@
+package mydnshost_go_api
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseZone parses a BIND-format zone file for the given domain into records, with names relative to the domain
+// and hostnames in the record data fully qualified, as the API stores them. The $ORIGIN and $TTL directives are
+// supported, but $INCLUDE is not. SOA records are skipped, as the API manages the SOA separately.
+func ParseZone(zone, domain string) ([]Record, error) {
+	p := &zoneParser{domain: normaliseHost(domain), origin: normaliseHost(domain)}
+
+	scanner := bufio.NewScanner(strings.NewReader(zone))
+	var entry []string
+	ownerOmitted, depth, line, start := false, 0, 0, 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if depth == 0 {
+			ownerOmitted = len(text) > 0 && (text[0] == ' ' || text[0] == '\t')
+			start = line
+		}
+
+		tokens, parens, err := zoneTokens(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entry = append(entry, tokens...)
+		if depth += parens; depth < 0 {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", line)
+		}
+
+		if depth == 0 && len(entry) > 0 {
+			if err := p.entry(entry, ownerOmitted); err != nil {
+				return nil, fmt.Errorf("line %d: %w", start, err)
+			}
+			entry = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", start)
+	}
+	return p.records, nil
+}
+
+type zoneParser struct {
+	domain     string
+	origin     string
+	defaultTTL int
+	lastTTL    int
+	owner      string
+	records    []Record
+}
+
+func (p *zoneParser) entry(tokens []string, ownerOmitted bool) error {
+	switch strings.ToUpper(tokens[0]) {
+	case "$ORIGIN":
+		if len(tokens) != 2 {
+			return errors.New("$ORIGIN requires a single name")
+		}
+		p.origin = p.qualify(tokens[1])
+		return nil
+	case "$TTL":
+		if len(tokens) != 2 {
+			return errors.New("$TTL requires a single value")
+		}
+		ttl, err := parseZoneTTL(tokens[1])
+		if err != nil {
+			return err
+		}
+		p.defaultTTL = ttl
+		return nil
+	case "$INCLUDE":
+		return errors.New("$INCLUDE is not supported")
+	}
+
+	if !ownerOmitted {
+		p.owner = p.qualify(tokens[0])
+		tokens = tokens[1:]
+	} else if p.owner == "" {
+		return errors.New("record has no owner name")
+	}
+
+	ttl := -1
+	for len(tokens) > 0 {
+		if strings.EqualFold(tokens[0], "IN") {
+			tokens = tokens[1:]
+		} else if v, err := parseZoneTTL(tokens[0]); err == nil && ttl < 0 {
+			ttl = v
+			tokens = tokens[1:]
+		} else {
+			break
+		}
+	}
+	if len(tokens) == 0 {
+		return errors.New("record has no type")
+	}
+
+	switch {
+	case ttl >= 0:
+		p.lastTTL = ttl
+	case p.defaultTTL > 0:
+		ttl = p.defaultTTL
+	default:
+		ttl = p.lastTTL
+	}
+
+	name, err := p.relative(p.owner)
+	if err != nil {
+		return err
+	}
+
+	record := Record{Name: name, Type: strings.ToUpper(tokens[0]), TTL: ttl}
+	data := tokens[1:]
+	if len(data) == 0 {
+		return fmt.Errorf("%s record has no data", record.Type)
+	}
+
+	switch record.Type {
+	case "SOA":
+		return nil
+	case "MX", "SRV":
+		if len(data) < 2 {
+			return fmt.Errorf("invalid %s record", record.Type)
+		}
+		priority, err := strconv.Atoi(data[0])
+		if err != nil {
+			return fmt.Errorf("invalid %s priority: %s", record.Type, data[0])
+		}
+		record.Priority = &priority
+		data = data[1:]
+		data[len(data)-1] = p.qualify(data[len(data)-1])
+		record.Content = strings.Join(data, " ")
+	case "CNAME", "NS", "PTR", "DNAME":
+		if len(data) != 1 {
+			return fmt.Errorf("invalid %s record", record.Type)
+		}
+		record.Content = p.qualify(data[0])
+	case "TXT", "SPF":
+		// Quoted strings are concatenated, as resolvers do; unquoted text is taken as written.
+		if strings.HasPrefix(data[0], `"`) {
+			for i := range data {
+				record.Content += strings.Trim(data[i], `"`)
+			}
+		} else {
+			record.Content = strings.Join(data, " ")
+		}
+	default:
+		record.Content = strings.Join(data, " ")
+	}
+
+	if record.Content == "" {
+		return fmt.Errorf("%s record has no data", record.Type)
+	}
+	p.records = append(p.records, record)
+	return nil
+}
+
+// qualify converts a name from the zone file into a fully-qualified hostname without a trailing dot.
+func (p *zoneParser) qualify(name string) string {
+	switch {
+	case name == "@":
+		return p.origin
+	case strings.HasSuffix(name, "."):
+		return normaliseHost(name)
+	default:
+		return strings.ToLower(name) + "." + p.origin
+	}
+}
+
+// relative converts a fully-qualified name into a record name relative to the domain.
+func (p *zoneParser) relative(name string) (string, error) {
+	if name == p.domain {
+		return "", nil
+	}
+	if strings.HasSuffix(name, "."+p.domain) {
+		return strings.TrimSuffix(name, "."+p.domain), nil
+	}
+	return "", fmt.Errorf("%s is outside of domain %s", name, p.domain)
+}
+
+// zoneTokens splits a line of a zone file into whitespace-separated tokens, keeping quoted strings (with their
+// quotes) as single tokens and discarding comments. It also returns the change in parenthesis depth.
+func zoneTokens(line string) ([]string, int, error) {
+	var tokens []string
+	var token strings.Builder
+	quoted, depth := false, 0
+
+	flush := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case quoted && ch == '\\' && i+1 < len(line):
+			i++
+			token.WriteByte(line[i])
+		case ch == '"':
+			quoted = !quoted
+			token.WriteByte(ch)
+		case quoted:
+			token.WriteByte(ch)
+		case ch == ';':
+			flush()
+			return tokens, depth, nil
+		case ch == '(' || ch == ')':
+			flush()
+			if ch == '(' {
+				depth++
+			} else {
+				depth--
+			}
+		case ch == ' ' || ch == '\t':
+			flush()
+		default:
+			token.WriteByte(ch)
+		}
+	}
+
+	if quoted {
+		return nil, 0, errors.New("unterminated quoted string")
+	}
+	flush()
+	return tokens, depth, nil
+}
+
+// parseZoneTTL parses a TTL as either a number of seconds or a BIND-style duration such as "1h30m".
+func parseZoneTTL(value string) (int, error) {
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+		return n, nil
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total, n, digits, parsed := 0, 0, false, false
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if ch >= '0' && ch <= '9' {
+			n = n*10 + int(ch-'0')
+			digits = true
+			continue
+		}
+
+		unit, ok := units[ch|0x20]
+		if !ok || !digits {
+			return 0, fmt.Errorf("invalid TTL: %s", value)
+		}
+		total += n * unit
+		n, digits, parsed = 0, false, true
+	}
+
+	if digits || !parsed {
+		return 0, fmt.Errorf("invalid TTL: %s", value)
+	}
+	return total, nil
+}
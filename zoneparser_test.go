@@ -0,0 +1,74 @@
+package mydnshost_go_api
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testZone = `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.mydnshost.co.uk. dnsadmin.example.com. (
+		2020091301 ; serial
+		86400 7200 2419200 60 )
+	IN	NS	ns1.mydnshost.co.uk.
+@	300	IN	A	192.0.2.1
+www	IN	CNAME	@
+	IN	TXT	"v=spf1 " "-all"
+mail	IN 1d	MX	10 mx1
+_sip._tcp	SRV	10 60 5060 sip.example.net.
+$ORIGIN dev.example.com.
+api	AAAA	2001:db8::1 ; comment
+txt	TXT	a plain record
+`
+
+func TestParseZone(t *testing.T) {
+	records, err := ParseZone(testZone, "Example.com.")
+	if err != nil {
+		t.Fatalf("ParseZone() error = %v", err)
+	}
+
+	want := []Record{
+		{Name: "", Type: "NS", Content: "ns1.mydnshost.co.uk", TTL: 3600},
+		{Name: "", Type: "A", Content: "192.0.2.1", TTL: 300},
+		{Name: "www", Type: "CNAME", Content: "example.com", TTL: 3600},
+		{Name: "www", Type: "TXT", Content: "v=spf1 -all", TTL: 3600},
+		{Name: "mail", Type: "MX", Content: "mx1.example.com", TTL: 86400, Priority: intPtr(10)},
+		{Name: "_sip._tcp", Type: "SRV", Content: "60 5060 sip.example.net", TTL: 3600, Priority: intPtr(10)},
+		{Name: "api.dev", Type: "AAAA", Content: "2001:db8::1", TTL: 3600},
+		{Name: "txt.dev", Type: "TXT", Content: "a plain record", TTL: 3600},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("ParseZone() =\n%+v\nwant\n%+v", records, want)
+	}
+}
+
+func TestParseZoneInvalid(t *testing.T) {
+	tests := map[string]string{
+		"outside domain":    "www.example.net. IN A 192.0.2.1",
+		"include":           "$INCLUDE other.zone",
+		"no owner":          "  IN A 192.0.2.1",
+		"no data":           "www IN A",
+		"bad priority":      "@ MX mail.example.com.",
+		"unbalanced":        "@ SOA ns1 admin ( 1 2 3 4",
+		"unterminated text": `@ TXT "open`,
+	}
+	for name, zone := range tests {
+		if _, err := ParseZone(zone, "example.com"); err == nil {
+			t.Errorf("ParseZone() of %s should fail", name)
+		}
+	}
+}
+
+func TestParseZoneTTL(t *testing.T) {
+	tests := map[string]int{"0": 0, "3600": 3600, "1h": 3600, "1h30m": 5400, "2W": 1209600}
+	for input, want := range tests {
+		if got, err := parseZoneTTL(input); err != nil || got != want {
+			t.Errorf("parseZoneTTL(%q) = %d, %v, want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "A", "MX", "1h30", "-1"} {
+		if _, err := parseZoneTTL(input); err == nil {
+			t.Errorf("parseZoneTTL(%q) should fail", input)
+		}
+	}
+}